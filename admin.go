@@ -0,0 +1,219 @@
+package ctxlog
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AdminOption defines a functional option for AdminHandler configuration
+type AdminOption func(*adminConfig)
+
+// adminConfig holds configuration for AdminHandler
+type adminConfig struct {
+	authorize func(r *http.Request) error
+}
+
+// WithAuthorizer creates an AdminOption that authorizes every request to the
+// admin handler. If the function returns an error, the request is rejected
+// with 403 Forbidden and the error message.
+func WithAuthorizer(authorize func(r *http.Request) error) AdminOption {
+	return func(cfg *adminConfig) {
+		cfg.authorize = authorize
+	}
+}
+
+// ScopeStatus represents the state of a scope reported by AdminHandler.
+type ScopeStatus struct {
+	Name     string         `json:"name"`
+	Parent   string         `json:"parent,omitempty"`
+	EnvVars  []string       `json:"envVars,omitempty"`
+	State    string         `json:"state"`
+	Level    string         `json:"level,omitempty"`
+	TTL      string         `json:"ttl,omitempty"`
+	Children []*ScopeStatus `json:"children,omitempty"`
+}
+
+// Global scope states reported in ScopeStatus.State
+const (
+	ScopeStatusDefault  = "default"  // no global rule, activation depends on parent and env vars
+	ScopeStatusEnabled  = "enabled"  // enabled by EnableScopeGlobal
+	ScopeStatusDisabled = "disabled" // denied by DenyScopeGlobal
+)
+
+var (
+	adminLevels     sync.Map  //nolint:gochecknoglobals // Required for levels set by AdminHandler
+	adminLevelsOnce sync.Once //nolint:gochecknoglobals // Required for levels set by AdminHandler
+)
+
+// setAdminLevel sets the scope level on behalf of AdminHandler, so that it is
+// reset when the scope is disabled or its enablement expires.
+func setAdminLevel(scope *Scope, level slog.Level) {
+	adminLevelsOnce.Do(func() {
+		OnScopeExpired(resetAdminLevel)
+	})
+	adminLevels.Store(scope, struct{}{})
+	SetScopeLevel(scope, level)
+}
+
+// resetAdminLevel resets the scope level if it was set by AdminHandler.
+func resetAdminLevel(scope *Scope) {
+	if _, ok := adminLevels.LoadAndDelete(scope); ok {
+		ResetScopeLevel(scope)
+	}
+}
+
+// adminHandler serves the scope admin API.
+type adminHandler struct {
+	cfg adminConfig
+	mux *http.ServeMux
+}
+
+// AdminHandler returns an http.Handler to inspect and toggle scopes at runtime.
+//
+// Endpoints (relative to where the handler is mounted):
+//
+//	GET    /        list all registered scopes as a tree
+//	POST   /{name}  enable the scope globally (EnableScopeGlobal)
+//	DELETE /{name}  remove global enablement of the scope (DisableScopeGlobal)
+//
+// POST accepts optional query parameters: "ttl" (e.g. "10m") enables the scope
+// with EnableScopeGlobalFor so that it expires after the duration, and "level"
+// (e.g. "debug") sets the scope level with SetScopeLevel. A level set by POST
+// is reset by DELETE and when the TTL expires.
+//
+// Example:
+//
+//	http.Handle("/debug/scopes/", http.StripPrefix("/debug/scopes",
+//	    ctxlog.AdminHandler(ctxlog.WithAuthorizer(checkAdminToken))))
+func AdminHandler(options ...AdminOption) http.Handler {
+	h := &adminHandler{
+		mux: http.NewServeMux(),
+	}
+	for _, opt := range options {
+		opt(&h.cfg)
+	}
+
+	h.mux.HandleFunc("GET /{$}", h.list)
+	h.mux.HandleFunc("POST /{name}", h.enable)
+	h.mux.HandleFunc("DELETE /{name}", h.disable)
+
+	return h
+}
+
+func (h *adminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.cfg.authorize != nil {
+		if err := h.cfg.authorize(r); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *adminHandler) list(w http.ResponseWriter, _ *http.Request) {
+	roots := make([]*ScopeStatus, 0)
+	for _, scope := range Scopes() {
+		if scope.parent == nil {
+			roots = append(roots, scope.status(true))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, roots)
+}
+
+func (h *adminHandler) enable(w http.ResponseWriter, r *http.Request) {
+	scope, ok := LookupScope(r.PathValue("name"))
+	if !ok {
+		http.Error(w, "scope not found", http.StatusNotFound)
+		return
+	}
+
+	var ttl time.Duration
+	if v := r.URL.Query().Get("ttl"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			http.Error(w, "invalid ttl", http.StatusBadRequest)
+			return
+		}
+		ttl = d
+	}
+
+	if v := r.URL.Query().Get("level"); v != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(v)); err != nil {
+			http.Error(w, "invalid level", http.StatusBadRequest)
+			return
+		}
+		setAdminLevel(scope, level)
+	}
+
+	if ttl > 0 {
+		EnableScopeGlobalFor(ttl, scope)
+	} else {
+		EnableScopeGlobal(scope)
+	}
+	writeJSON(w, http.StatusOK, scope.status(false))
+}
+
+func (h *adminHandler) disable(w http.ResponseWriter, r *http.Request) {
+	scope, ok := LookupScope(r.PathValue("name"))
+	if !ok {
+		http.Error(w, "scope not found", http.StatusNotFound)
+		return
+	}
+
+	DisableScopeGlobal(scope)
+	resetAdminLevel(scope)
+	writeJSON(w, http.StatusOK, scope.status(false))
+}
+
+// status returns the admin status of the scope, including children if recursive.
+func (s *Scope) status(recursive bool) *ScopeStatus {
+	st := &ScopeStatus{
+		Name:  s.name,
+		State: ScopeStatusDefault,
+	}
+	if s.parent != nil {
+		st.Parent = s.parent.name
+	}
+	if envVars := s.EnvVars(); len(envVars) > 0 {
+		st.EnvVars = envVars
+	}
+
+	// Apply a passed deadline first so that the state reflects it
+	s.expireGlobal()
+	flags := s.flags.Load()
+	switch {
+	case flags&scopeFlagDeny != 0:
+		st.State = ScopeStatusDisabled
+	case flags&scopeFlagAllow != 0:
+		st.State = ScopeStatusEnabled
+		if ttl, ok := s.GlobalTTL(); ok {
+			st.TTL = ttl.Round(time.Second).String()
+		}
+	}
+
+	if level, ok := s.resolveLevel(nil); ok {
+		st.Level = level.String()
+	}
+
+	if recursive {
+		for _, child := range s.Children() {
+			st.Children = append(st.Children, child.status(true))
+		}
+		sort.Slice(st.Children, func(i, j int) bool { return st.Children[i].Name < st.Children[j].Name })
+	}
+
+	return st
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
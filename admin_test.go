@@ -0,0 +1,173 @@
+package ctxlog_test
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/ctxlog"
+)
+
+func findStatus(statuses []*ctxlog.ScopeStatus, name string) *ctxlog.ScopeStatus {
+	for _, st := range statuses {
+		if st.Name == name {
+			return st
+		}
+		if found := findStatus(st.Children, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func TestAdminHandlerList(t *testing.T) {
+	parent := ctxlog.NewScope("admin-list", ctxlog.EnabledBy("ADMIN_LIST"), ctxlog.WithLevel(slog.LevelDebug))
+	child := parent.NewChild("child")
+	ctxlog.EnableScopeGlobal(child)
+	t.Cleanup(func() { ctxlog.ResetScopeGlobal(child) })
+
+	rec := httptest.NewRecorder()
+	ctxlog.AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	var statuses []*ctxlog.ScopeStatus
+	if err := json.NewDecoder(rec.Body).Decode(&statuses); err != nil {
+		t.Fatal(err)
+	}
+
+	st := findStatus(statuses, "admin-list")
+	if st == nil {
+		t.Fatal("Expected admin-list scope in response")
+	}
+	if len(st.EnvVars) != 1 || st.EnvVars[0] != "ADMIN_LIST" ||
+		st.Level != "DEBUG" || st.State != ctxlog.ScopeStatusDefault {
+		t.Errorf("Unexpected parent status: %+v", st)
+	}
+	if len(st.Children) != 1 || st.Children[0].Name != "admin-list.child" {
+		t.Fatalf("Expected child in tree: %+v", st.Children)
+	}
+	if st.Children[0].Parent != "admin-list" || st.Children[0].State != ctxlog.ScopeStatusEnabled {
+		t.Errorf("Unexpected child status: %+v", st.Children[0])
+	}
+}
+
+func TestAdminHandlerToggle(t *testing.T) {
+	scope := ctxlog.NewScope("admin-toggle")
+	t.Cleanup(func() {
+		ctxlog.ResetScopeGlobal(scope)
+		ctxlog.ResetScopeLevel(scope)
+	})
+	handler := ctxlog.AdminHandler()
+	ctx := t.Context()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin-toggle?level=debug", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelDebug) {
+		t.Error("POST should enable the scope with the given level")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin-toggle", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("DELETE should disable the scope")
+	}
+	if enabledCtx := ctxlog.EnableScope(ctx, scope); ctxlog.From(enabledCtx, scope).Enabled(enabledCtx, slog.LevelDebug) {
+		t.Error("DELETE should reset the level set by POST")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin-unknown", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown scope, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin-toggle?ttl=abc", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid ttl, got %d", rec.Code)
+	}
+}
+
+func TestAdminHandlerTTL(t *testing.T) {
+	clock := useFakeClock(t)
+	scope := ctxlog.NewScope("admin-ttl")
+	t.Cleanup(func() {
+		ctxlog.ResetScopeGlobal(scope)
+		ctxlog.ResetScopeLevel(scope)
+	})
+	ctx := t.Context()
+
+	rec := httptest.NewRecorder()
+	ctxlog.AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin-ttl?ttl=20ms&level=debug", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be enabled before TTL expires")
+	}
+
+	clock.Advance(20 * time.Millisecond)
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be disabled after TTL expires")
+	}
+
+	if enabledCtx := ctxlog.EnableScope(ctx, scope); ctxlog.From(enabledCtx, scope).Enabled(enabledCtx, slog.LevelDebug) {
+		t.Error("Level set by POST should be reset after TTL expires")
+	}
+}
+
+func TestAdminHandlerExpiredStatus(t *testing.T) {
+	clock := useFakeClock(t)
+	scope := ctxlog.NewScope("admin-expired")
+	t.Cleanup(func() { ctxlog.ResetScopeGlobal(scope) })
+
+	ctxlog.EnableScopeGlobalFor(time.Minute, scope)
+	clock.Advance(time.Minute)
+
+	// The first GET after the deadline already reports the expiration
+	rec := httptest.NewRecorder()
+	ctxlog.AdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	var statuses []*ctxlog.ScopeStatus
+	if err := json.NewDecoder(rec.Body).Decode(&statuses); err != nil {
+		t.Fatal(err)
+	}
+	st := findStatus(statuses, "admin-expired")
+	if st == nil || st.State != ctxlog.ScopeStatusDefault || st.TTL != "" {
+		t.Errorf("Expected expired scope in default state, got %+v", st)
+	}
+}
+
+func TestAdminHandlerAuthorizer(t *testing.T) {
+	handler := ctxlog.AdminHandler(ctxlog.WithAuthorizer(func(r *http.Request) error {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			return errors.New("unauthorized")
+		}
+		return nil
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 without token, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 with token, got %d", rec.Code)
+	}
+}
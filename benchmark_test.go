@@ -1,6 +1,7 @@
 package ctxlog_test
 
 import (
+	"log/slog"
 	"testing"
 
 	"github.com/m-mizutani/ctxlog"
@@ -43,3 +44,90 @@ func BenchmarkWithoutSampling(b *testing.B) {
 		logger.Info("benchmark message")
 	}
 }
+
+func BenchmarkSampleKey(b *testing.B) {
+	ctx := b.Context()
+	scope := ctxlog.NewScope("bench-sample-key", ctxlog.EnabledBy("BENCH_SAMPLE_KEY"))
+	ctx = ctxlog.EnableScope(ctx, scope)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger := ctxlog.From(ctx, scope, ctxlog.WithSampleKey("request-id-0123456789", 0.5))
+		logger.Info("benchmark message")
+	}
+}
+
+func BenchmarkSampleKeyParallel(b *testing.B) {
+	ctx := b.Context()
+	scope := ctxlog.NewScope("bench-sample-key-parallel", ctxlog.EnabledBy("BENCH_SAMPLE_KEY_PARALLEL"))
+	ctx = ctxlog.EnableScope(ctx, scope)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger := ctxlog.From(ctx, scope, ctxlog.WithSampleKey("request-id-0123456789", 0.5))
+			logger.Info("benchmark message")
+		}
+	})
+}
+
+func newDeepScope(b *testing.B, name string) (*ctxlog.Scope, *ctxlog.Scope) {
+	b.Helper()
+	root := ctxlog.NewScope(name, ctxlog.EnabledBy("BENCH_DEEP_ROOT"))
+	leaf := root
+	for _, child := range []string{"l2", "l3", "l4", "l5"} {
+		leaf = leaf.NewChild(child, ctxlog.EnabledBy("BENCH_DEEP_"+child))
+	}
+	return root, leaf
+}
+
+func BenchmarkFromDeepHierarchyInactive(b *testing.B) {
+	ctx := b.Context()
+	_, leaf := newDeepScope(b, "bench-deep-inactive")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ctxlog.From(ctx, leaf)
+	}
+}
+
+func BenchmarkFromDeepHierarchyActive(b *testing.B) {
+	ctx := b.Context()
+	root, leaf := newDeepScope(b, "bench-deep-active")
+	ctx = ctxlog.EnableScope(ctx, root)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ctxlog.From(ctx, leaf)
+	}
+}
+
+func BenchmarkFromDeepHierarchyActiveWithAttrs(b *testing.B) {
+	ctx := b.Context()
+	root, leaf := newDeepScope(b, "bench-deep-attrs")
+	ctx = ctxlog.EnableScope(ctx, root)
+	ctx = ctxlog.WithAttrs(ctx, slog.String("request_id", "r1"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ctxlog.From(ctx, leaf)
+	}
+}
+
+func BenchmarkFromDeepHierarchyParallel(b *testing.B) {
+	ctx := b.Context()
+	root, leaf := newDeepScope(b, "bench-deep-parallel")
+	ctxlog.EnableScopeGlobal(root)
+	defer ctxlog.ResetScopeGlobal(root)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = ctxlog.From(ctx, leaf)
+		}
+	})
+}
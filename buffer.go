@@ -0,0 +1,201 @@
+package ctxlog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// BufferOptions configures a Buffer created by NewBuffer.
+type BufferOptions struct {
+	// MaxRecords is the maximum number of buffered records. When exceeded, the
+	// oldest records are dropped. Zero means no limit.
+	MaxRecords int
+	// MaxBytes is the maximum approximate size of buffered records in bytes.
+	// When exceeded, the oldest records are dropped. Zero means no limit.
+	MaxBytes int
+	// FlushLevel is the level that triggers flushing the buffer.
+	// Defaults to slog.LevelError.
+	FlushLevel slog.Leveler
+}
+
+// Buffer holds log records of a request until it is flushed or discarded.
+type Buffer struct {
+	opts    BufferOptions
+	entries []bufferedRecord
+	size    int
+	dropped int
+	flushed atomic.Bool // read without mu so that Enabled stays lock-free
+	mu      sync.Mutex  // guards entries, size and dropped
+}
+
+// bufferedRecord is a record with the handler it must be written to.
+type bufferedRecord struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+	size    int
+}
+
+// bufferHandler implements slog.Handler to buffer log records.
+type bufferHandler struct {
+	buffer *Buffer
+	base   slog.Handler
+}
+
+// NewBuffer creates a new context whose logger buffers all records, including
+// those below the base handler's level, for the lifetime of a request.
+// The buffer is written to the base handler when a record at FlushLevel
+// (Error by default) appears or Flush is called, and dropped by Discard.
+// After flushing, records are passed through to the base handler directly.
+//
+// Example:
+//
+//	ctx, buf := ctxlog.NewBuffer(ctx, ctxlog.BufferOptions{MaxRecords: 1000})
+//	defer buf.Discard()               // drop debug logs of successful requests
+//	ctxlog.From(ctx).Debug("details")  // buffered
+//	ctxlog.From(ctx).Error("failed")   // flushes "details" and "failed"
+func NewBuffer(ctx context.Context, opts BufferOptions) (context.Context, *Buffer) {
+	if opts.FlushLevel == nil {
+		opts.FlushLevel = slog.LevelError
+	}
+
+	buffer := &Buffer{opts: opts}
+	handler := &bufferHandler{
+		buffer: buffer,
+		base:   embeddedLogger(ctx).Handler(),
+	}
+
+	return With(ctx, slog.New(handler)), buffer
+}
+
+func (h *bufferHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.buffer.flushed.Load() {
+		return h.base.Enabled(ctx, level)
+	}
+	return true
+}
+
+//nolint:gocritic // slog.Record must be passed by value per slog.Handler interface
+func (h *bufferHandler) Handle(ctx context.Context, record slog.Record) error {
+	b := h.buffer
+	if b.flushed.Load() {
+		return h.passThrough(ctx, record)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// The buffer may have been flushed while waiting for the lock
+	if b.flushed.Load() {
+		return h.passThrough(ctx, record)
+	}
+
+	b.push(bufferedRecord{
+		ctx:     ctx,
+		handler: h.base,
+		record:  record.Clone(),
+		size:    recordSize(&record),
+	})
+
+	if record.Level >= b.opts.FlushLevel.Level() {
+		return b.flushLocked()
+	}
+	return nil
+}
+
+// passThrough writes the record to the base handler after the buffer is flushed.
+//
+//nolint:gocritic // slog.Record is passed by value as received by Handle
+func (h *bufferHandler) passThrough(ctx context.Context, record slog.Record) error {
+	if !h.base.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return h.base.Handle(ctx, record)
+}
+
+func (h *bufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &bufferHandler{buffer: h.buffer, base: h.base.WithAttrs(attrs)}
+}
+
+func (h *bufferHandler) WithGroup(name string) slog.Handler {
+	return &bufferHandler{buffer: h.buffer, base: h.base.WithGroup(name)}
+}
+
+// push appends an entry and drops the oldest entries exceeding the limits.
+func (b *Buffer) push(entry bufferedRecord) {
+	b.entries = append(b.entries, entry)
+	b.size += entry.size
+
+	for len(b.entries) > 1 &&
+		((b.opts.MaxRecords > 0 && len(b.entries) > b.opts.MaxRecords) ||
+			(b.opts.MaxBytes > 0 && b.size > b.opts.MaxBytes)) {
+		b.size -= b.entries[0].size
+		b.entries[0] = bufferedRecord{}
+		b.entries = b.entries[1:]
+		b.dropped++
+	}
+}
+
+// flushLocked writes all buffered records to their handlers. Caller must hold b.mu.
+func (b *Buffer) flushLocked() error {
+	var errs []error
+	for i := range b.entries {
+		entry := &b.entries[i]
+		if err := entry.handler.Handle(entry.ctx, entry.record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	b.entries = nil
+	b.size = 0
+	// Set after writing, so that records passing through follow the flushed ones
+	b.flushed.Store(true)
+	return errors.Join(errs...)
+}
+
+// Flush writes all buffered records to the base handler and switches the
+// buffer to pass-through mode.
+func (b *Buffer) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.flushLocked()
+}
+
+// Discard drops all buffered records without writing them.
+func (b *Buffer) Discard() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries = nil
+	b.size = 0
+}
+
+// Len returns the number of buffered records.
+func (b *Buffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.entries)
+}
+
+// Dropped returns the number of records dropped because of the buffer limits.
+func (b *Buffer) Dropped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.dropped
+}
+
+// recordSize estimates the size of a record in bytes.
+func recordSize(record *slog.Record) int {
+	size := len(record.Message)
+	record.Attrs(func(attr slog.Attr) bool {
+		size += len(attr.Key) + len(attr.Value.String())
+		return true
+	})
+	return size
+}
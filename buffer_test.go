@@ -0,0 +1,105 @@
+package ctxlog_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/ctxlog"
+)
+
+func TestBufferFlushOnError(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(&buf, nil)))
+	ctx, buffer := ctxlog.NewBuffer(ctx, ctxlog.BufferOptions{})
+
+	logger := ctxlog.From(ctx)
+	logger.Debug("debug details", "step", 1)
+	logger.With("user", "alice").Info("processing")
+
+	if buf.Len() != 0 {
+		t.Errorf("Records should be buffered until flush: %s", buf.String())
+	}
+	if buffer.Len() != 2 {
+		t.Errorf("Expected 2 buffered records, got %d", buffer.Len())
+	}
+
+	logger.Error("request failed")
+
+	out := buf.String()
+	for _, want := range []string{"debug details", "msg=processing user=alice", "request failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in flushed output: %s", want, out)
+		}
+	}
+
+	// After flush, records pass through honoring the base level
+	logger.Info("after failure")
+	logger.Debug("debug after failure")
+	if !strings.Contains(buf.String(), "after failure") {
+		t.Error("Records after flush should be written directly")
+	}
+	if strings.Contains(buf.String(), "debug after failure") {
+		t.Error("Records after flush should honor the base handler level")
+	}
+}
+
+func TestBufferDiscard(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(&buf, nil)))
+	ctx, buffer := ctxlog.NewBuffer(ctx, ctxlog.BufferOptions{})
+
+	ctxlog.From(ctx).Info("successful request")
+	buffer.Discard()
+
+	if err := buffer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Discarded records should not be written: %s", buf.String())
+	}
+}
+
+func TestBufferMaxRecords(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(&buf, nil)))
+	ctx, buffer := ctxlog.NewBuffer(ctx, ctxlog.BufferOptions{MaxRecords: 2})
+
+	logger := ctxlog.From(ctx)
+	logger.Info("first")
+	logger.Info("second")
+	logger.Info("third")
+
+	if buffer.Dropped() != 1 {
+		t.Errorf("Expected 1 dropped record, got %d", buffer.Dropped())
+	}
+	if err := buffer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "first") || !strings.Contains(out, "second") || !strings.Contains(out, "third") {
+		t.Errorf("Oldest record should be dropped: %s", out)
+	}
+}
+
+func TestBufferWithScope(t *testing.T) {
+	var buf bytes.Buffer
+	scope := ctxlog.NewScope("buffer-scope", ctxlog.EnabledBy("BUFFER_SCOPE"))
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(&buf, nil)))
+	ctx, buffer := ctxlog.NewBuffer(ctx, ctxlog.BufferOptions{})
+
+	ctxlog.From(ctx, scope).Info("inactive scope")
+	ctxlog.From(ctxlog.EnableScope(ctx, scope), scope).Debug("active scope")
+
+	if buffer.Len() != 1 {
+		t.Errorf("Only records from active scopes should be buffered, got %d", buffer.Len())
+	}
+	if err := buffer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "msg=\"active scope\" ctxlog.scope=buffer-scope") {
+		t.Errorf("Flushed record should include scope attribute: %s", buf.String())
+	}
+}
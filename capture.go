@@ -2,61 +2,274 @@ package ctxlog
 
 import (
 	"context"
+	"io"
 	"log/slog"
-	"os"
+	"math"
+	"slices"
+	"strings"
 	"sync"
+	"testing"
+	"time"
 )
 
 // Capture holds captured log records for testing.
 type Capture struct {
-	records []slog.Record
-	mu      sync.RWMutex
+	records    []CapturedRecord
+	seq        uint64 // number of records appended so far
+	dropped    int
+	maxRecords int
+	ring       bool
+	handler    *captureHandler // root handler, used by Sub
+	mu         sync.RWMutex
+	cond       *sync.Cond // signaled when a record is appended, with mu locked
+}
+
+// CaptureMark is a position in a Capture returned by Mark.
+type CaptureMark uint64
+
+// CapturedRecord is a captured log record with its fully resolved attributes,
+// including those added via Logger.With and WithGroup.
+type CapturedRecord struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	// Attrs holds attribute values by key. Groups are nested as map[string]any.
+	Attrs map[string]any
+	// Scope is the name of the scope the record was logged in, or empty.
+	Scope string
+	// Groups are the groups opened via WithGroup when the record was logged.
+	Groups []string
+	// Record is the resolved record with the attributes nested in groups.
+	Record slog.Record
+}
+
+// CaptureOptions configures NewCapture. The zero value captures records of
+// all levels and writes nothing.
+type CaptureOptions struct {
+	// Level is the minimum level of captured records. Nil captures all levels.
+	Level slog.Leveler
+	// Passthrough is a handler that also receives captured records,
+	// subject to its own level.
+	Passthrough slog.Handler
+	// Output is a writer that captured records are written to in text format.
+	Output io.Writer
+	// TB writes captured records with TB.Log, so they only appear when the
+	// test fails or runs with -v.
+	TB testing.TB
+	// MaxRecords limits the number of kept records. Zero means no limit.
+	// Records beyond the limit are dropped unless Ring is set.
+	MaxRecords int
+	// Ring makes a capture with MaxRecords drop the oldest records instead
+	// of new ones.
+	Ring bool
 }
 
 // captureHandler implements slog.Handler to capture log records.
 type captureHandler struct {
 	capture *Capture
-	base    slog.Handler
+	level   slog.Leveler
+	outputs []slog.Handler
+	attrs   []groupedAttr // attributes added by WithAttrs
+	groups  []string      // groups opened by WithGroup
+	scope   string        // scope name added by From
+	tap     bool          // Enabled follows the first output instead of level
+}
+
+// groupedAttr is an attribute with the groups it belongs to.
+type groupedAttr struct {
+	groups []string
+	attr   slog.Attr
 }
 
 func (h *captureHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return h.base.Enabled(ctx, level)
+	if h.tap {
+		return h.outputs[0].Enabled(ctx, level)
+	}
+	return h.level == nil || level >= h.level.Level()
 }
 
 //nolint:gocritic // slog.Record must be passed by value per slog.Handler interface
 func (h *captureHandler) Handle(ctx context.Context, record slog.Record) error {
-	// Make a copy to avoid issues with record reuse
-	recordCopy := record.Clone()
+	// Resolve attributes added by WithAttrs and WithGroup into a new record,
+	// which also avoids issues with record reuse
+	resolved := h.resolve(&record)
+	captured := CapturedRecord{
+		Time:    resolved.Time,
+		Level:   resolved.Level,
+		Message: resolved.Message,
+		Attrs:   attrMap(&resolved),
+		Scope:   h.scope,
+		Groups:  h.groups,
+		Record:  resolved,
+	}
 
-	h.capture.mu.Lock()
-	h.capture.records = append(h.capture.records, recordCopy)
-	h.capture.mu.Unlock()
+	h.capture.add(&captured)
 
-	return h.base.Handle(ctx, record)
+	for _, output := range h.outputs {
+		if !output.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := output.Handle(ctx, record.Clone()); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (h *captureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	outputs := make([]slog.Handler, len(h.outputs))
+	for i, output := range h.outputs {
+		outputs[i] = output.WithAttrs(attrs)
+	}
+
+	scope := h.scope
+	grouped := make([]groupedAttr, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(grouped, h.attrs)
+	for _, attr := range attrs {
+		if attr.Key == scopeAttrKey {
+			scope = attr.Value.String()
+		}
+		grouped = append(grouped, groupedAttr{groups: h.groups, attr: attr})
+	}
+
 	return &captureHandler{
 		capture: h.capture,
-		base:    h.base.WithAttrs(attrs),
+		level:   h.level,
+		outputs: outputs,
+		attrs:   grouped,
+		groups:  h.groups,
+		scope:   scope,
+		tap:     h.tap,
 	}
 }
 
 func (h *captureHandler) WithGroup(name string) slog.Handler {
+	outputs := make([]slog.Handler, len(h.outputs))
+	for i, output := range h.outputs {
+		outputs[i] = output.WithGroup(name)
+	}
+
+	groups := make([]string, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+	groups = append(groups, name)
+
 	return &captureHandler{
 		capture: h.capture,
-		base:    h.base.WithGroup(name),
+		level:   h.level,
+		outputs: outputs,
+		attrs:   h.attrs,
+		groups:  groups,
+		scope:   h.scope,
+		tap:     h.tap,
+	}
+}
+
+// resolve returns a new record with the attributes added by WithAttrs followed
+// by the record's own attributes, nested in their groups.
+func (h *captureHandler) resolve(record *slog.Record) slog.Record {
+	entries := make([]groupedAttr, len(h.attrs), len(h.attrs)+record.NumAttrs())
+	copy(entries, h.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		entries = append(entries, groupedAttr{groups: h.groups, attr: attr})
+		return true
+	})
+
+	resolved := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	resolved.AddAttrs(nestAttrs(entries, 0)...)
+	return resolved
+}
+
+// nestAttrs builds attributes at the given group depth, merging attributes of
+// the same group into one group attribute in order of first appearance.
+func nestAttrs(entries []groupedAttr, depth int) []slog.Attr {
+	var attrs []slog.Attr
+	groupIndex := make(map[string]int)
+	groupEntries := make(map[string][]groupedAttr)
+
+	for _, entry := range entries {
+		if len(entry.groups) == depth {
+			attrs = append(attrs, entry.attr)
+			continue
+		}
+
+		name := entry.groups[depth]
+		if _, exists := groupIndex[name]; !exists {
+			groupIndex[name] = len(attrs)
+			attrs = append(attrs, slog.Attr{Key: name})
+		}
+		groupEntries[name] = append(groupEntries[name], entry)
+	}
+
+	for name, index := range groupIndex {
+		attrs[index].Value = slog.GroupValue(nestAttrs(groupEntries[name], depth+1)...)
+	}
+	return attrs
+}
+
+// attrMap converts the record's attributes into a map, nesting groups as
+// map[string]any.
+func attrMap(record *slog.Record) map[string]any {
+	attrs := make(map[string]any, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(attrs, attr)
+		return true
+	})
+	return attrs
+}
+
+// addAttr adds an attribute to the map, merging groups of the same name.
+func addAttr(attrs map[string]any, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() != slog.KindGroup {
+		attrs[attr.Key] = value.Any()
+		return
+	}
+
+	// Attributes of an unnamed group are inlined, as slog handlers do
+	target := attrs
+	if attr.Key != "" {
+		group, ok := attrs[attr.Key].(map[string]any)
+		if !ok {
+			group = make(map[string]any)
+			attrs[attr.Key] = group
+		}
+		target = group
+	}
+	for _, child := range value.Group() {
+		addAttr(target, child)
 	}
 }
 
 // NewCapture creates a new context with log capture capability.
-func NewCapture(ctx context.Context) (context.Context, *Capture) {
-	capture := &Capture{}
+// By default, records of all levels are captured and nothing is written.
+// Use CaptureOptions to forward records to a handler, a writer or a test log.
+//
+// Example:
+//
+//	ctx, capture := ctxlog.NewCapture(t.Context(), ctxlog.CaptureOptions{TB: t})
+func NewCapture(ctx context.Context, opts ...CaptureOptions) (context.Context, *Capture) {
+	var opt CaptureOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
 
-	// Create a capture handler that wraps a text handler that outputs to discard
+	capture := newCapture(opt.MaxRecords, opt.Ring)
 	handler := &captureHandler{
 		capture: capture,
-		base:    slog.NewTextHandler(os.Stdout, nil),
+		level:   opt.Level,
+	}
+	capture.handler = handler
+
+	// Output handlers accept all levels the capture handler passes
+	textOpts := &slog.HandlerOptions{Level: slog.Level(math.MinInt)}
+	if opt.Passthrough != nil {
+		handler.outputs = append(handler.outputs, opt.Passthrough)
+	}
+	if opt.Output != nil {
+		handler.outputs = append(handler.outputs, slog.NewTextHandler(opt.Output, textOpts))
+	}
+	if opt.TB != nil {
+		handler.outputs = append(handler.outputs, slog.NewTextHandler(&tbWriter{tb: opt.TB}, textOpts))
 	}
 
 	logger := slog.New(handler)
@@ -64,6 +277,137 @@ func NewCapture(ctx context.Context) (context.Context, *Capture) {
 	return With(ctx, logger), capture
 }
 
+// Tap captures records logged with the returned context while still sending
+// them to the logger embedded in ctx. Unlike NewCapture, the embedded logger's
+// handler chain, level and pre-bound attributes are preserved, and only
+// records it enables are captured. Attributes bound to the embedded logger
+// before Tap are not visible in the captured records.
+//
+// Example:
+//
+//	ctx = ctxlog.With(ctx, productionLogger)
+//	ctx, capture := ctxlog.Tap(ctx)
+//	HandleRequest(ctx) // logs reach productionLogger and capture
+func Tap(ctx context.Context) (context.Context, *Capture) {
+	capture := newCapture(0, false)
+	handler := &captureHandler{
+		capture: capture,
+		outputs: []slog.Handler{embeddedLogger(ctx).Handler()},
+		tap:     true,
+	}
+	capture.handler = handler
+
+	return With(ctx, slog.New(handler)), capture
+}
+
+// newCapture creates an empty Capture with the given memory bounds.
+func newCapture(maxRecords int, ring bool) *Capture {
+	capture := &Capture{maxRecords: maxRecords, ring: ring}
+	capture.cond = sync.NewCond(&capture.mu)
+	return capture
+}
+
+// add appends a record within the memory bounds and wakes waiters.
+func (c *Capture) add(record *CapturedRecord) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.maxRecords > 0 && len(c.records) >= c.maxRecords {
+		c.dropped++
+		if !c.ring {
+			return
+		}
+		// Dropped records are released when append reallocates
+		c.records = c.records[len(c.records)-c.maxRecords+1:]
+	}
+
+	c.records = append(c.records, *record)
+	c.seq++
+	c.cond.Broadcast()
+}
+
+// since returns the kept records appended at or after seq. It must be
+// called with mu locked; the returned slice must not be modified.
+func (c *Capture) since(seq uint64) []CapturedRecord {
+	first := c.seq - uint64(len(c.records))
+	if seq <= first {
+		return c.records[0:len(c.records):len(c.records)]
+	}
+	if seq >= c.seq {
+		return nil
+	}
+	return c.records[seq-first : len(c.records) : len(c.records)]
+}
+
+// Sub creates a child capture. Records logged with the returned context are
+// captured both by the child and by c, including its outputs. The child
+// captures the records c captures and has the same memory bounds.
+//
+// Example:
+//
+//	for _, tc := range cases {
+//		ctx, sub := capture.Sub(ctx)
+//		run(ctx, tc)
+//		sub.AssertLogged(t, ctxlog.ByMessage(tc.pattern))
+//	}
+func (c *Capture) Sub(ctx context.Context) (context.Context, *Capture) {
+	sub := newCapture(c.maxRecords, c.ring)
+	handler := &captureHandler{
+		capture: sub,
+		outputs: []slog.Handler{c.handler},
+		tap:     true,
+	}
+	sub.handler = handler
+
+	return With(ctx, slog.New(handler)), sub
+}
+
+// Reset removes all captured records. Marks taken before remain valid and
+// match no earlier records.
+func (c *Capture) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.records = nil
+	c.dropped = 0
+}
+
+// Mark returns the current position, so that Since returns records captured
+// after it.
+func (c *Capture) Mark() CaptureMark {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return CaptureMark(c.seq)
+}
+
+// Since returns the kept records captured after mark.
+func (c *Capture) Since(mark CaptureMark) []CapturedRecord {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Clone(c.since(uint64(mark)))
+}
+
+// Dropped returns the number of records dropped due to MaxRecords.
+func (c *Capture) Dropped() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.dropped
+}
+
+// tbWriter writes each log line with testing.TB.Log.
+type tbWriter struct {
+	tb testing.TB
+}
+
+func (w *tbWriter) Write(p []byte) (int, error) {
+	w.tb.Helper()
+	w.tb.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
 // Messages returns all captured log messages.
 func (c *Capture) Messages() []string {
 	c.mu.RLock()
@@ -83,6 +427,18 @@ func (c *Capture) Records() []slog.Record {
 	defer c.mu.RUnlock()
 
 	records := make([]slog.Record, len(c.records))
+	for i := range c.records {
+		records[i] = c.records[i].Record
+	}
+	return records
+}
+
+// Captured returns all captured records with their resolved attributes.
+func (c *Capture) Captured() []CapturedRecord {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	records := make([]CapturedRecord, len(c.records))
 	copy(records, c.records)
 	return records
 }
@@ -0,0 +1,215 @@
+package ctxlog
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Matcher matches captured log records.
+type Matcher interface {
+	// Match reports whether the record matches.
+	Match(record *CapturedRecord) bool
+	// String describes the condition for failure messages.
+	String() string
+}
+
+// matcherFunc implements Matcher with a function.
+type matcherFunc struct {
+	desc  string
+	match func(record *CapturedRecord) bool
+}
+
+func (m matcherFunc) Match(record *CapturedRecord) bool {
+	return m.match(record)
+}
+
+func (m matcherFunc) String() string {
+	return m.desc
+}
+
+// MatchFunc creates a Matcher from a function. desc describes the condition
+// in failure messages of AssertLogged and AssertNotLogged.
+func MatchFunc(desc string, match func(record *CapturedRecord) bool) Matcher {
+	return matcherFunc{desc: desc, match: match}
+}
+
+// ByLevel matches records at the given level.
+func ByLevel(level slog.Level) Matcher {
+	return MatchFunc("level="+level.String(), func(record *CapturedRecord) bool {
+		return record.Level == level
+	})
+}
+
+// ByMessage matches records whose message matches the regular expression.
+func ByMessage(re *regexp.Regexp) Matcher {
+	return MatchFunc("msg=~/"+re.String()+"/", func(record *CapturedRecord) bool {
+		return re.MatchString(record.Message)
+	})
+}
+
+// ByAttr matches records with the attribute key equal to value. The key may be
+// a dot-separated path into groups, e.g. "request.id". See (*Capture).Attr.
+func ByAttr(key string, value any) Matcher {
+	expected := slog.AnyValue(value)
+	return MatchFunc(fmt.Sprintf("%s=%v", key, value), func(record *CapturedRecord) bool {
+		actual, ok := lookupAttr(&record.Record, key)
+		if !ok {
+			return false
+		}
+		// Value.Equal compares KindAny values with ==, which panics on slices and maps
+		if actual.Kind() == slog.KindAny && expected.Kind() == slog.KindAny {
+			return reflect.DeepEqual(actual.Any(), expected.Any())
+		}
+		return actual.Equal(expected)
+	})
+}
+
+// ByScope matches records logged by a logger of the given scope.
+func ByScope(scope *Scope) Matcher {
+	return MatchFunc("scope="+scope.name, func(record *CapturedRecord) bool {
+		return record.Scope == scope.name
+	})
+}
+
+// Filter returns captured records matching all matchers.
+//
+// Example:
+//
+//	records := capture.Filter(ctxlog.ByLevel(slog.LevelError), ctxlog.ByAttr("user_id", 42))
+func (c *Capture) Filter(matchers ...Matcher) []CapturedRecord {
+	var matched []CapturedRecord
+	for _, record := range c.Captured() {
+		if matchAll(&record, matchers) {
+			matched = append(matched, record)
+		}
+	}
+	return matched
+}
+
+// Count returns the number of captured records matching all matchers.
+func (c *Capture) Count(matchers ...Matcher) int {
+	return len(c.Filter(matchers...))
+}
+
+// Attr returns the value of the attribute key in the record, including
+// attributes added via Logger.With. The key may be a dot-separated path into
+// groups, e.g. "request.id"; an attribute whose key contains a dot, such as
+// "ctxlog.scope", is matched as is first.
+//
+//nolint:gocritic // CapturedRecord is passed by value as returned by Filter
+func (c *Capture) Attr(record CapturedRecord, key string) (slog.Value, bool) {
+	return lookupAttr(&record.Record, key)
+}
+
+// AssertLogged reports a test error if no captured record matches all matchers.
+func (c *Capture) AssertLogged(t testing.TB, matchers ...Matcher) {
+	t.Helper()
+
+	if c.Count(matchers...) == 0 {
+		t.Errorf("expected a log record matching %s, but none found\ncaptured records:\n%s",
+			describeMatchers(matchers), formatRecords(c.Captured()))
+	}
+}
+
+// AssertNotLogged reports a test error if any captured record matches all matchers.
+func (c *Capture) AssertNotLogged(t testing.TB, matchers ...Matcher) {
+	t.Helper()
+
+	if matched := c.Filter(matchers...); len(matched) > 0 {
+		t.Errorf("expected no log record matching %s, but found %d\nmatched records:\n%s",
+			describeMatchers(matchers), len(matched), formatRecords(matched))
+	}
+}
+
+// matchAll reports whether the record matches all matchers.
+func matchAll(record *CapturedRecord, matchers []Matcher) bool {
+	for _, m := range matchers {
+		if !m.Match(record) {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupAttr finds an attribute by key or by a dot-separated group path.
+func lookupAttr(record *slog.Record, key string) (slog.Value, bool) {
+	var attrs []slog.Attr
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return findAttr(attrs, key)
+}
+
+// findAttr finds an attribute in attrs by key or by a dot-separated group path.
+func findAttr(attrs []slog.Attr, key string) (slog.Value, bool) {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return attr.Value.Resolve(), true
+		}
+	}
+
+	// Walk into groups whose name is a prefix of the key
+	for _, attr := range attrs {
+		value := attr.Value.Resolve()
+		if value.Kind() != slog.KindGroup {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(key, attr.Key+"."); ok {
+			if found, ok := findAttr(value.Group(), rest); ok {
+				return found, true
+			}
+		}
+	}
+
+	return slog.Value{}, false
+}
+
+// describeMatchers describes matchers for failure messages.
+func describeMatchers(matchers []Matcher) string {
+	if len(matchers) == 0 {
+		return "{any}"
+	}
+
+	descs := make([]string, len(matchers))
+	for i, m := range matchers {
+		descs[i] = m.String()
+	}
+	return "{" + strings.Join(descs, ", ") + "}"
+}
+
+// formatRecords formats records one per line for failure messages.
+func formatRecords(records []CapturedRecord) string {
+	if len(records) == 0 {
+		return "  (none)"
+	}
+
+	var b strings.Builder
+	for i := range records {
+		fmt.Fprintf(&b, "  [%d] %s msg=%q", i, records[i].Level, records[i].Message)
+		records[i].Record.Attrs(func(attr slog.Attr) bool {
+			writeAttr(&b, "", attr)
+			return true
+		})
+		if i < len(records)-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// writeAttr writes an attribute as key=value, flattening groups with dots.
+func writeAttr(b *strings.Builder, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		for _, child := range value.Group() {
+			writeAttr(b, prefix+attr.Key+".", child)
+		}
+		return
+	}
+	fmt.Fprintf(b, " %s%s=%v", prefix, attr.Key, value.Any())
+}
@@ -0,0 +1,107 @@
+package ctxlog_test
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/ctxlog"
+)
+
+func TestCaptureFilter(t *testing.T) {
+	apiScope := ctxlog.NewScope("capture_filter_api")
+	ctx, capture := ctxlog.NewCapture(t.Context())
+	ctx = ctxlog.EnableScope(ctx, apiScope)
+
+	ctxlog.From(ctx).Info("request started", "user_id", 42)
+	ctxlog.From(ctx).Error("request failed", "user_id", 42)
+	ctxlog.From(ctx).Error("request failed", "user_id", 7)
+	ctxlog.From(ctx, apiScope).Error("api failed", "user_id", 42)
+
+	records := capture.Filter(ctxlog.ByLevel(slog.LevelError), ctxlog.ByAttr("user_id", 42))
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 error records for user 42, got %d", len(records))
+	}
+
+	if n := capture.Count(ctxlog.ByMessage(regexp.MustCompile(`^request`))); n != 3 {
+		t.Errorf("Expected 3 records matching message, got %d", n)
+	}
+	if n := capture.Count(ctxlog.ByScope(apiScope)); n != 1 {
+		t.Errorf("Expected 1 record of scope, got %d", n)
+	}
+	if n := capture.Count(); n != 4 {
+		t.Errorf("Expected Count without matchers to return all records, got %d", n)
+	}
+}
+
+func TestCaptureAttr(t *testing.T) {
+	ctx, capture := ctxlog.NewCapture(t.Context())
+	ctx = ctxlog.WithAttrs(ctx, slog.String("service", "api"))
+
+	logger := ctxlog.From(ctx).With("user_id", 42).WithGroup("request").With("id", "r1")
+	logger.Info("handled", "status", 200, slog.Group("client", "ip", "127.0.0.1"))
+
+	records := capture.Captured()
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+
+	for key, expected := range map[string]any{
+		"service":           "api",
+		"user_id":           int64(42),
+		"request.id":        "r1",
+		"request.status":    int64(200),
+		"request.client.ip": "127.0.0.1",
+	} {
+		value, ok := capture.Attr(records[0], key)
+		if !ok || value.Any() != expected {
+			t.Errorf("Expected %s=%v, got %v (found: %v)", key, expected, value, ok)
+		}
+	}
+
+	if _, ok := capture.Attr(records[0], "status"); ok {
+		t.Error("Expected grouped attribute not to be found at top level")
+	}
+	if n := capture.Count(ctxlog.ByAttr("request.id", "r1")); n != 1 {
+		t.Errorf("Expected ByAttr to match grouped attribute, got %d", n)
+	}
+}
+
+func TestCaptureFilterUncomparableAttr(t *testing.T) {
+	ctx, capture := ctxlog.NewCapture(t.Context())
+	ctxlog.From(ctx).Info("tagged", "tags", []string{"a"}, "meta", map[string]int{"n": 1})
+
+	if n := capture.Count(ctxlog.ByAttr("tags", []string{"a"})); n != 1 {
+		t.Errorf("Expected ByAttr to match slice attribute, got %d", n)
+	}
+	if n := capture.Count(ctxlog.ByAttr("meta", map[string]int{"n": 1})); n != 1 {
+		t.Errorf("Expected ByAttr to match map attribute, got %d", n)
+	}
+	if n := capture.Count(ctxlog.ByAttr("tags", []string{"b"})); n != 0 {
+		t.Errorf("Expected ByAttr not to match different slice, got %d", n)
+	}
+}
+
+func TestCaptureAssert(t *testing.T) {
+	ctx, capture := ctxlog.NewCapture(t.Context())
+	ctxlog.From(ctx).Info("user logged in", "user_id", 42)
+
+	capture.AssertLogged(t, ctxlog.ByLevel(slog.LevelInfo), ctxlog.ByAttr("user_id", 42))
+	capture.AssertNotLogged(t, ctxlog.ByLevel(slog.LevelError))
+
+	tb := &recordingTB{TB: t}
+	capture.AssertLogged(tb, ctxlog.ByLevel(slog.LevelError))
+	capture.AssertNotLogged(tb, ctxlog.ByAttr("user_id", 42))
+
+	if len(tb.errors) != 2 {
+		t.Fatalf("Expected 2 assertion failures, got %v", tb.errors)
+	}
+	if !strings.Contains(tb.errors[0], "{level=ERROR}") ||
+		!strings.Contains(tb.errors[0], `INFO msg="user logged in" user_id=42`) {
+		t.Errorf("Expected failure to describe conditions and captured records, got %q", tb.errors[0])
+	}
+	if !strings.Contains(tb.errors[1], "{user_id=42}") || !strings.Contains(tb.errors[1], "found 1") {
+		t.Errorf("Expected failure to describe matched records, got %q", tb.errors[1])
+	}
+}
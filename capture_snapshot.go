@@ -0,0 +1,201 @@
+package ctxlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// maskedValue replaces masked attribute values in snapshots.
+const maskedValue = "<masked>"
+
+// SnapshotOptions configures MatchSnapshot.
+type SnapshotOptions struct {
+	// MaskKeys are attribute keys whose values are masked. Keys in groups are
+	// dot-separated paths, e.g. "request.id".
+	MaskKeys []string
+	// MaskKinds are value kinds that are masked, e.g. slog.KindDuration.
+	// Time values are always masked.
+	MaskKinds []slog.Kind
+	// MaskValues are patterns masked within string values, e.g. UUIDs.
+	MaskValues []*regexp.Regexp
+	// Update rewrites the golden file instead of comparing. It is also
+	// enabled by a boolean "update" flag defined by the test binary.
+	Update bool
+}
+
+// MatchSnapshot compares the captured records with the golden file at path and
+// reports a test error with a diff if they differ. Records are written one per
+// line as JSON with sorted keys; timestamps are omitted and volatile values are
+// masked per SnapshotOptions.
+//
+// To rewrite golden files with "go test -update", define the flag in the test
+// package:
+//
+//	var _ = flag.Bool("update", false, "update golden files")
+func (c *Capture) MatchSnapshot(t testing.TB, path string, opts ...SnapshotOptions) {
+	t.Helper()
+
+	var opt SnapshotOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	actual, err := c.snapshot(&opt)
+	if err != nil {
+		t.Errorf("ctxlog: failed to serialize snapshot: %v", err)
+		return
+	}
+
+	if opt.Update || updateFlag() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Errorf("ctxlog: failed to create snapshot directory: %v", err)
+			return
+		}
+		if err := os.WriteFile(path, actual, 0o600); err != nil {
+			t.Errorf("ctxlog: failed to update snapshot: %v", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path) //nolint:gosec // path is given by the test
+	if errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ctxlog: snapshot %s does not exist; run the test with -update to create it\nactual:\n%s", path, actual)
+		return
+	}
+	if err != nil {
+		t.Errorf("ctxlog: failed to read snapshot: %v", err)
+		return
+	}
+
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("ctxlog: captured records do not match snapshot %s (-expected +actual):\n%s",
+			path, diffLines(string(expected), string(actual)))
+	}
+}
+
+// updateFlag reports whether a boolean "update" flag is set.
+func updateFlag() bool {
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	update, ok := getter.Get().(bool)
+	return ok && update
+}
+
+// snapshotRecord is the serialized form of a captured record.
+type snapshotRecord struct {
+	Level   string         `json:"level"`
+	Message string         `json:"msg"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// snapshot serializes the captured records.
+func (c *Capture) snapshot(opt *SnapshotOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	for _, record := range c.Captured() {
+		entry := snapshotRecord{
+			Level:   record.Level.String(),
+			Message: record.Message,
+			Attrs:   make(map[string]any),
+		}
+		record.Record.Attrs(func(attr slog.Attr) bool {
+			snapshotAttr(entry.Attrs, "", attr, opt)
+			return true
+		})
+
+		// Encode writes one line per record
+		if err := encoder.Encode(entry); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// snapshotAttr adds a normalized attribute to attrs. prefix is the dotted path
+// of the enclosing groups.
+func snapshotAttr(attrs map[string]any, prefix string, attr slog.Attr, opt *SnapshotOptions) {
+	path := prefix + attr.Key
+	value := attr.Value.Resolve()
+
+	switch {
+	case slices.Contains(opt.MaskKeys, path), slices.Contains(opt.MaskKinds, value.Kind()):
+		attrs[attr.Key] = maskedValue
+	case value.Kind() == slog.KindGroup:
+		target := attrs
+		if attr.Key != "" {
+			group, ok := attrs[attr.Key].(map[string]any)
+			if !ok {
+				group = make(map[string]any)
+				attrs[attr.Key] = group
+			}
+			target = group
+			prefix = path + "."
+		}
+		for _, child := range value.Group() {
+			snapshotAttr(target, prefix, child, opt)
+		}
+	case value.Kind() == slog.KindTime:
+		attrs[attr.Key] = maskedValue
+	case value.Kind() == slog.KindString:
+		s := value.String()
+		for _, re := range opt.MaskValues {
+			s = re.ReplaceAllString(s, maskedValue)
+		}
+		attrs[attr.Key] = s
+	case value.Kind() == slog.KindAny:
+		// Values of arbitrary types are compared in their printed form
+		attrs[attr.Key] = fmt.Sprintf("%+v", value.Any())
+	case value.Kind() == slog.KindDuration:
+		attrs[attr.Key] = value.Duration().String()
+	default:
+		attrs[attr.Key] = value.Any()
+	}
+}
+
+// diffLines returns a line-by-line diff of expected and actual.
+func diffLines(expected, actual string) string {
+	expectedLines := strings.Split(strings.TrimSuffix(expected, "\n"), "\n")
+	actualLines := strings.Split(strings.TrimSuffix(actual, "\n"), "\n")
+
+	var b strings.Builder
+	for i := range max(len(expectedLines), len(actualLines)) {
+		var e, a string
+		hasExpected, hasActual := i < len(expectedLines), i < len(actualLines)
+		if hasExpected {
+			e = expectedLines[i]
+		}
+		if hasActual {
+			a = actualLines[i]
+		}
+
+		if hasExpected && hasActual && e == a {
+			fmt.Fprintf(&b, "  %s\n", e)
+			continue
+		}
+		if hasExpected {
+			fmt.Fprintf(&b, "- %s\n", e)
+		}
+		if hasActual {
+			fmt.Fprintf(&b, "+ %s\n", a)
+		}
+	}
+	return b.String()
+}
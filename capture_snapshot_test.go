@@ -0,0 +1,59 @@
+package ctxlog_test
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/ctxlog"
+)
+
+var _ = flag.Bool("update", false, "update golden files")
+
+func TestCaptureMatchSnapshot(t *testing.T) {
+	ctx, capture := ctxlog.NewCapture(t.Context())
+	logger := ctxlog.From(ctx).With("request_id", "5f0c6a4e-8d1b-4c5e-9f3a-2b7d1e6c8a90")
+
+	logger.Info("request started", "path", "/users", "at", time.Now())
+	logger.WithGroup("response").Info("request finished",
+		"status", 200,
+		"elapsed", 123*time.Millisecond,
+		"session", "abc",
+	)
+
+	capture.MatchSnapshot(t, "testdata/capture_snapshot.golden", ctxlog.SnapshotOptions{
+		MaskKeys:   []string{"response.session"},
+		MaskKinds:  []slog.Kind{slog.KindDuration},
+		MaskValues: []*regexp.Regexp{regexp.MustCompile(`[0-9a-f]{8}(-[0-9a-f]{4}){3}-[0-9a-f]{12}`)},
+	})
+}
+
+func TestCaptureMatchSnapshotMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.golden")
+
+	ctx, capture := ctxlog.NewCapture(t.Context())
+	ctxlog.From(ctx).Info("first")
+
+	tb := &recordingTB{TB: t}
+	capture.MatchSnapshot(tb, path)
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "does not exist") {
+		t.Fatalf("Expected missing snapshot error, got %v", tb.errors)
+	}
+
+	capture.MatchSnapshot(t, path, ctxlog.SnapshotOptions{Update: true})
+	if data, err := os.ReadFile(path); err != nil || string(data) != `{"level":"INFO","msg":"first"}`+"\n" {
+		t.Fatalf("Expected snapshot to be written, got %q, %v", data, err)
+	}
+
+	ctxlog.From(ctx).Info("second")
+	tb = &recordingTB{TB: t}
+	capture.MatchSnapshot(tb, path)
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], `+ {"level":"INFO","msg":"second"}`) {
+		t.Errorf("Expected diff with added record, got %v", tb.errors)
+	}
+}
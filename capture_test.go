@@ -1,6 +1,11 @@
 package ctxlog_test
 
 import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/m-mizutani/ctxlog"
@@ -35,3 +40,223 @@ func TestCapture(t *testing.T) {
 		t.Errorf("Expected 2 records, got %d", len(records))
 	}
 }
+
+func TestCaptureAllLevels(t *testing.T) {
+	ctx, capture := ctxlog.NewCapture(t.Context())
+	logger := ctxlog.From(ctx)
+
+	logger.Debug("debug message")
+	logger.Info("info message")
+
+	if messages := capture.Messages(); len(messages) != 2 || messages[0] != "debug message" {
+		t.Errorf("Expected debug records to be captured by default, got %v", messages)
+	}
+}
+
+func TestCaptureOptions(t *testing.T) {
+	var output, passthrough bytes.Buffer
+	ctx, capture := ctxlog.NewCapture(t.Context(), ctxlog.CaptureOptions{
+		Level:       slog.LevelInfo,
+		Passthrough: slog.NewJSONHandler(&passthrough, &slog.HandlerOptions{Level: slog.LevelWarn}),
+		Output:      &output,
+	})
+	logger := ctxlog.From(ctx).With("user", "alice")
+
+	logger.Debug("debug message")
+	logger.Info("info message")
+	logger.Warn("warn message")
+
+	if messages := capture.Messages(); len(messages) != 2 {
+		t.Errorf("Expected records below Level to be skipped, got %v", messages)
+	}
+	if !strings.Contains(output.String(), `msg="info message" user=alice`) ||
+		!strings.Contains(output.String(), "warn message") {
+		t.Errorf("Expected captured records in output: %s", output.String())
+	}
+	if strings.Contains(passthrough.String(), "info message") ||
+		!strings.Contains(passthrough.String(), `"msg":"warn message","user":"alice"`) {
+		t.Errorf("Passthrough handler should receive records by its own level: %s", passthrough.String())
+	}
+}
+
+func TestCaptureTB(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	ctx, _ := ctxlog.NewCapture(t.Context(), ctxlog.CaptureOptions{TB: tb})
+	ctxlog.From(ctx).Info("logged to test", "key", "value")
+
+	if len(tb.logs) != 1 || !strings.Contains(tb.logs[0], `msg="logged to test" key=value`) {
+		t.Errorf("Expected record written to TB.Log, got %v", tb.logs)
+	}
+}
+
+// recordingTB records calls to Log and Errorf for testing.
+type recordingTB struct {
+	testing.TB
+	logs   []string
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Log(args ...any) {
+	r.logs = append(r.logs, fmt.Sprint(args...))
+}
+
+func TestCapturedRecord(t *testing.T) {
+	scope := ctxlog.NewScope("captured_record")
+	ctx, capture := ctxlog.NewCapture(t.Context())
+	ctx = ctxlog.EnableScope(ctx, scope)
+
+	logger := ctxlog.From(ctx, scope).With("user_id", 42).WithGroup("request").With("id", "r1")
+	logger.Warn("slow request", "elapsed", 3, slog.Group("client", "ip", "127.0.0.1"))
+
+	records := capture.Captured()
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+
+	record := records[0]
+	if record.Level != slog.LevelWarn || record.Message != "slow request" || record.Time.IsZero() {
+		t.Errorf("Unexpected record header: %v %q %v", record.Level, record.Message, record.Time)
+	}
+	if record.Scope != "captured_record" {
+		t.Errorf("Expected scope captured_record, got %q", record.Scope)
+	}
+	if len(record.Groups) != 1 || record.Groups[0] != "request" {
+		t.Errorf("Expected groups [request], got %v", record.Groups)
+	}
+
+	expected := map[string]any{
+		"ctxlog.scope": "captured_record",
+		"user_id":      int64(42),
+		"request": map[string]any{
+			"id":      "r1",
+			"elapsed": int64(3),
+			"client":  map[string]any{"ip": "127.0.0.1"},
+		},
+	}
+	if !reflect.DeepEqual(record.Attrs, expected) {
+		t.Errorf("Expected attrs %v, got %v", expected, record.Attrs)
+	}
+
+	// Records returns the same resolved attributes as slog records
+	var keys []string
+	capture.Records()[0].Attrs(func(attr slog.Attr) bool {
+		keys = append(keys, attr.Key)
+		return true
+	})
+	if strings.Join(keys, ",") != "ctxlog.scope,user_id,request" {
+		t.Errorf("Expected resolved record attrs in order, got %v", keys)
+	}
+}
+
+func TestCaptureResetAndMark(t *testing.T) {
+	ctx, capture := ctxlog.NewCapture(t.Context())
+	logger := ctxlog.From(ctx)
+
+	logger.Info("setup")
+	mark := capture.Mark()
+	logger.Info("case 1")
+
+	if records := capture.Since(mark); len(records) != 1 || records[0].Message != "case 1" {
+		t.Errorf("Expected records since mark, got %v", records)
+	}
+
+	capture.Reset()
+	if messages := capture.Messages(); len(messages) != 0 {
+		t.Errorf("Expected no records after reset, got %v", messages)
+	}
+
+	logger.Info("case 2")
+	if records := capture.Since(mark); len(records) != 1 || records[0].Message != "case 2" {
+		t.Errorf("Expected mark to remain valid after reset, got %v", records)
+	}
+}
+
+func TestCaptureSub(t *testing.T) {
+	var output bytes.Buffer
+	ctx, capture := ctxlog.NewCapture(t.Context(), ctxlog.CaptureOptions{Level: slog.LevelInfo, Output: &output})
+	ctxlog.From(ctx).Info("parent")
+
+	subCtx, sub := capture.Sub(ctx)
+	ctxlog.From(subCtx).With("case", 1).Info("child")
+	ctxlog.From(subCtx).Debug("below level")
+
+	if messages := sub.Messages(); len(messages) != 1 || messages[0] != "child" {
+		t.Errorf("Expected sub-capture to hold only its records, got %v", messages)
+	}
+	if messages := capture.Messages(); len(messages) != 2 || messages[1] != "child" {
+		t.Errorf("Expected parent to also capture child records, got %v", messages)
+	}
+	if n := capture.Count(ctxlog.ByAttr("case", 1)); n != 1 {
+		t.Errorf("Expected parent to capture child attrs, got %d", n)
+	}
+	if !strings.Contains(output.String(), "msg=child case=1") {
+		t.Errorf("Expected child records written to parent output, got %q", output.String())
+	}
+}
+
+func TestCaptureMaxRecords(t *testing.T) {
+	t.Run("drop new", func(t *testing.T) {
+		ctx, capture := ctxlog.NewCapture(t.Context(), ctxlog.CaptureOptions{MaxRecords: 2})
+		for i := range 5 {
+			ctxlog.From(ctx).Info(fmt.Sprint(i))
+		}
+
+		if messages := capture.Messages(); strings.Join(messages, ",") != "0,1" {
+			t.Errorf("Expected first records kept, got %v", messages)
+		}
+		if capture.Dropped() != 3 {
+			t.Errorf("Expected 3 dropped, got %d", capture.Dropped())
+		}
+	})
+
+	t.Run("ring", func(t *testing.T) {
+		ctx, capture := ctxlog.NewCapture(t.Context(), ctxlog.CaptureOptions{MaxRecords: 2, Ring: true})
+		mark := capture.Mark()
+		for i := range 5 {
+			ctxlog.From(ctx).Info(fmt.Sprint(i))
+		}
+
+		if messages := capture.Messages(); strings.Join(messages, ",") != "3,4" {
+			t.Errorf("Expected last records kept, got %v", messages)
+		}
+		if capture.Dropped() != 3 {
+			t.Errorf("Expected 3 dropped, got %d", capture.Dropped())
+		}
+		if records := capture.Since(mark); len(records) != 2 {
+			t.Errorf("Expected Since to return kept records, got %d", len(records))
+		}
+	})
+}
+
+func TestTap(t *testing.T) {
+	var output bytes.Buffer
+	base := slog.New(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelInfo})).With("service", "api")
+
+	ctx := ctxlog.With(t.Context(), base)
+	ctx = ctxlog.WithAttrs(ctx, slog.String("request_id", "r1"))
+	ctx, capture := ctxlog.Tap(ctx)
+
+	ctxlog.From(ctx).Debug("below base level")
+	ctxlog.From(ctx).Info("handled", "status", 200)
+
+	if messages := capture.Messages(); len(messages) != 1 || messages[0] != "handled" {
+		t.Errorf("Expected only records enabled by the base logger, got %v", messages)
+	}
+	if n := capture.Count(ctxlog.ByAttr("request_id", "r1"), ctxlog.ByAttr("status", 200)); n != 1 {
+		t.Errorf("Expected context attrs to be captured, got %d", n)
+	}
+
+	line := output.String()
+	for _, want := range []string{`"msg":"handled"`, `"service":"api"`, `"request_id":"r1"`, `"status":200`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected base handler output to contain %s, got %q", want, line)
+		}
+	}
+	if strings.Count(line, "request_id") != 1 || strings.Contains(line, "below base level") {
+		t.Errorf("Expected base handler output once per enabled record, got %q", line)
+	}
+}
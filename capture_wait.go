@@ -0,0 +1,91 @@
+package ctxlog
+
+import (
+	"context"
+	"fmt"
+)
+
+// WaitFor blocks until a captured record matches all matchers and returns it.
+// Records captured before the call are checked first. If ctx is done before a
+// match, WaitFor returns an error listing the records captured so far.
+//
+// Matchers are called with the capture locked and must not call its methods.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+//	defer cancel()
+//	record, err := capture.WaitFor(ctx, ctxlog.ByMessage(regexp.MustCompile("job done")))
+func (c *Capture) WaitFor(ctx context.Context, matchers ...Matcher) (CapturedRecord, error) {
+	// Wake the waiter when ctx is done
+	stop := context.AfterFunc(ctx, func() {
+		c.mu.Lock()
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	})
+	defer stop()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for next := uint64(0); ; {
+		pending := c.since(next)
+		next = c.seq
+		for i := range pending {
+			if matchAll(&pending[i], matchers) {
+				return pending[i], nil
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return CapturedRecord{}, fmt.Errorf("ctxlog: no log record matching %s: %w\ncaptured records:\n%s",
+				describeMatchers(matchers), err, formatRecords(c.records))
+		}
+		c.cond.Wait()
+	}
+}
+
+// Subscribe returns a channel that receives records captured after the call,
+// in order. The channel is closed when ctx is done. Logging does not block on
+// a slow receiver; undelivered records are kept until received.
+func (c *Capture) Subscribe(ctx context.Context) <-chan CapturedRecord {
+	ch := make(chan CapturedRecord)
+
+	c.mu.RLock()
+	next := c.seq
+	c.mu.RUnlock()
+
+	stop := context.AfterFunc(ctx, func() {
+		c.mu.Lock()
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	})
+
+	go func() {
+		defer close(ch)
+		defer stop()
+
+		for {
+			c.mu.Lock()
+			for next >= c.seq && ctx.Err() == nil {
+				c.cond.Wait()
+			}
+			pending := c.since(next)
+			next = c.seq
+			c.mu.Unlock()
+
+			if ctx.Err() != nil {
+				return
+			}
+			for _, record := range pending {
+				select {
+				case ch <- record:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}
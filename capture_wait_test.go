@@ -0,0 +1,91 @@
+package ctxlog_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/ctxlog"
+)
+
+func TestCaptureWaitFor(t *testing.T) {
+	ctx, capture := ctxlog.NewCapture(t.Context())
+	ctxlog.From(ctx).Info("job started")
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		ctxlog.From(ctx).Info("job done", "id", 1)
+	}()
+
+	waitCtx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	record, err := capture.WaitFor(waitCtx, ctxlog.ByMessage(regexp.MustCompile("done")))
+	if err != nil {
+		t.Fatalf("Expected record, got error: %v", err)
+	}
+	if record.Message != "job done" || record.Attrs["id"] != int64(1) {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+
+	// Records captured before the call match immediately
+	record, err = capture.WaitFor(waitCtx, ctxlog.ByMessage(regexp.MustCompile("started")))
+	if err != nil || record.Message != "job started" {
+		t.Errorf("Expected existing record to match, got %+v, %v", record, err)
+	}
+}
+
+func TestCaptureWaitForTimeout(t *testing.T) {
+	ctx, capture := ctxlog.NewCapture(t.Context())
+	ctxlog.From(ctx).Info("job started")
+
+	waitCtx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := capture.WaitFor(waitCtx, ctxlog.ByLevel(slog.LevelError))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "{level=ERROR}") || !strings.Contains(err.Error(), `INFO msg="job started"`) {
+		t.Errorf("Expected error to list conditions and captured records, got %q", err)
+	}
+}
+
+func TestCaptureSubscribe(t *testing.T) {
+	ctx, capture := ctxlog.NewCapture(t.Context())
+	ctxlog.From(ctx).Info("before subscribe")
+
+	subCtx, cancel := context.WithCancel(t.Context())
+	records := capture.Subscribe(subCtx)
+
+	go func() {
+		for i := range 3 {
+			ctxlog.From(ctx).Info("message", "i", i)
+		}
+	}()
+
+	for i := range 3 {
+		select {
+		case record := <-records:
+			if record.Message != "message" || record.Attrs["i"] != int64(i) {
+				t.Errorf("Unexpected record %d: %+v", i, record)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for record")
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-records:
+		if ok {
+			t.Error("Expected no more records")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected channel to be closed after cancel")
+	}
+}
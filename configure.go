@@ -0,0 +1,153 @@
+package ctxlog
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+)
+
+// scopeRule is a parsed entry of a scope specification.
+type scopeRule struct {
+	pattern string
+	deny    bool
+	level   *slog.Level
+}
+
+// ConfigureFromEnv configures registered scopes from the specification in the
+// given environment variable. See ConfigureScopes for the format.
+// Nothing is changed if the variable is not set.
+//
+// Example:
+//
+//	export CTXLOG_SCOPES="api.*,database,-api.health,worker=debug"
+//
+//	warnings, err := ctxlog.ConfigureFromEnv("CTXLOG_SCOPES")
+func ConfigureFromEnv(envVar string) ([]string, error) {
+	spec, ok := os.LookupEnv(envVar)
+	if !ok {
+		return nil, nil
+	}
+	return ConfigureScopes(spec)
+}
+
+// ConfigureScopes enables and disables scopes globally by a comma-separated
+// specification. Each entry is a scope name or glob pattern (path.Match syntax):
+//
+//   - "name" or "pattern" enables matching scopes (like EnableScopeGlobal)
+//   - "-name" denies matching scopes (like DenyScopeGlobal)
+//   - "name=level" enables matching scopes and sets their level (like SetScopeLevel)
+//
+// Entries are applied in order, so later entries override earlier ones, e.g.
+// "api.*,-api.health" enables all api children except api.health. The rules are
+// also applied to scopes created later with NewScope. A new call replaces the
+// rules of a previous call: global enablement, deny and levels of scopes matched
+// by the previous rules are reset before the new rules are applied, so
+// ConfigureScopes("") undoes a configuration. Entries that match no registered
+// scope are returned as warnings so that typos are visible.
+func ConfigureScopes(spec string) ([]string, error) {
+	return defaultRegistry.Configure(spec)
+}
+
+// Configure applies a scope specification to the scopes in the registry.
+// See ConfigureScopes for the format.
+func (r *Registry) Configure(spec string) ([]string, error) {
+	rules, err := parseScopeSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Revert the effects of the previous rules
+	for name, scope := range r.scopes {
+		for _, rule := range r.rules {
+			if rule.match(name) {
+				rule.revert(scope)
+			}
+		}
+	}
+	r.rules = rules
+
+	var warnings []string
+	for _, rule := range rules {
+		matched := false
+		for name, scope := range r.scopes {
+			if rule.match(name) {
+				rule.apply(scope)
+				matched = true
+			}
+		}
+		if !matched {
+			warnings = append(warnings, fmt.Sprintf("ctxlog: no registered scope matches %q", rule.pattern))
+		}
+	}
+
+	return warnings, nil
+}
+
+// parseScopeSpec parses a comma-separated scope specification.
+func parseScopeSpec(spec string) ([]scopeRule, error) {
+	var rules []scopeRule
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		var rule scopeRule
+		if strings.HasPrefix(entry, "-") {
+			rule.deny = true
+			entry = entry[1:]
+		}
+
+		pattern, levelText, hasLevel := strings.Cut(entry, "=")
+		if hasLevel {
+			if rule.deny {
+				return nil, fmt.Errorf("ctxlog: level is not allowed for disabled scope %q", entry)
+			}
+			var level slog.Level
+			if err := level.UnmarshalText([]byte(levelText)); err != nil {
+				return nil, fmt.Errorf("ctxlog: invalid level for scope %q: %w", pattern, err)
+			}
+			rule.level = &level
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return nil, fmt.Errorf("ctxlog: invalid scope pattern %q", pattern)
+		}
+		rule.pattern = pattern
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// match reports whether the rule matches the scope name.
+func (r *scopeRule) match(name string) bool {
+	matched, _ := path.Match(r.pattern, name)
+	return matched
+}
+
+// revert removes the global state set by apply from the scope.
+func (r *scopeRule) revert(scope *Scope) {
+	ResetScopeGlobal(scope)
+	if r.level != nil {
+		ResetScopeLevel(scope)
+	}
+}
+
+// apply applies the rule to the scope globally.
+func (r *scopeRule) apply(scope *Scope) {
+	if r.deny {
+		DenyScopeGlobal(scope)
+		return
+	}
+
+	EnableScopeGlobal(scope)
+	if r.level != nil {
+		SetScopeLevel(scope, *r.level)
+	}
+}
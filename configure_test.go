@@ -0,0 +1,89 @@
+package ctxlog_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/m-mizutani/ctxlog"
+)
+
+func TestConfigureFromEnv(t *testing.T) {
+	apiScope := ctxlog.NewScope("cfg-api")
+	userScope := apiScope.NewChild("user")
+	healthScope := apiScope.NewChild("health")
+	dbScope := ctxlog.NewScope("cfg-database")
+	workerScope := ctxlog.NewScope("cfg-worker")
+	t.Cleanup(func() { _, _ = ctxlog.ConfigureScopes("") })
+
+	setScopeEnv(t, "CTXLOG_TEST_SCOPES", "cfg-api.*,cfg-database,-cfg-api.health,cfg-worker=debug,cfg-typo")
+	warnings, err := ctxlog.ConfigureFromEnv("CTXLOG_TEST_SCOPES")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(warnings) != 1 {
+		t.Errorf("Expected 1 warning for unknown scope, got %v", warnings)
+	}
+
+	ctx := t.Context()
+	for _, scope := range []*ctxlog.Scope{userScope, dbScope, workerScope} {
+		if !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+			t.Errorf("Scope %s should be enabled by spec", scope.Name())
+		}
+	}
+	if ctxlog.From(ctx, apiScope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Glob should not match the parent scope itself")
+	}
+	if ctxlog.From(ctx, healthScope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Negated entry should disable the scope")
+	}
+	if !ctxlog.From(ctx, workerScope).Enabled(ctx, slog.LevelDebug) {
+		t.Error("Level in spec should be applied to the scope")
+	}
+
+	// Scopes created later pick up the pattern
+	adminScope := apiScope.NewChild("admin")
+	if !ctxlog.From(ctx, adminScope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope created after configuration should pick up the pattern")
+	}
+
+	// A new configuration reverts the previous one
+	if _, err := ctxlog.ConfigureScopes("cfg-database"); err != nil {
+		t.Fatal(err)
+	}
+	for _, scope := range []*ctxlog.Scope{userScope, workerScope, adminScope} {
+		if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+			t.Errorf("Scope %s should be reset by the new configuration", scope.Name())
+		}
+	}
+	if !ctxlog.From(ctx, dbScope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be enabled by the new configuration")
+	}
+	enabledCtx := ctxlog.EnableScope(ctx, workerScope)
+	if ctxlog.From(enabledCtx, workerScope).Enabled(enabledCtx, slog.LevelDebug) {
+		t.Error("Level set by the previous configuration should be reset")
+	}
+
+	if _, err := ctxlog.ConfigureScopes(""); err != nil {
+		t.Fatal(err)
+	}
+	if ctxlog.From(ctx, dbScope).Enabled(ctx, slog.LevelInfo) ||
+		ctxlog.From(ctx, healthScope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Empty configuration should undo the previous one")
+	}
+}
+
+func TestConfigureScopesInvalid(t *testing.T) {
+	for _, spec := range []string{"api=verbose", "-api=debug", "api[", "="} {
+		if _, err := ctxlog.ConfigureScopes(spec); err == nil {
+			t.Errorf("Expected error for spec %q", spec)
+		}
+	}
+}
+
+func TestConfigureFromEnvUnset(t *testing.T) {
+	warnings, err := ctxlog.ConfigureFromEnv("CTXLOG_TEST_SCOPES_UNSET")
+	if err != nil || warnings != nil {
+		t.Errorf("Unset env var should be a no-op, got %v, %v", warnings, err)
+	}
+}
@@ -13,39 +13,38 @@ type ctxLoggerKey struct{}
 
 var loggerKey = ctxLoggerKey{} //nolint:gochecknoglobals // Required for context key
 
+type ctxAttrsKey struct{}
+
+var attrsKey = ctxAttrsKey{} //nolint:gochecknoglobals // Required for context key
+
 // From extracts a logger from the context with optional configuration.
 // If no logger is found, returns slog.Default().
 func From(ctx context.Context, options ...Option) *slog.Logger {
-	cfg := &config{}
-	for _, opt := range options {
-		opt.apply(cfg)
-	}
+	cfg := newConfig(options)
 
-	baseLogger := slog.Default()
-	if logger, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
-		baseLogger = logger
-	}
+	baseLogger := embeddedLogger(ctx)
 
 	// Check scope activation
+	var scope *Scope
 	if cfg.scope != nil {
-		if !cfg.scope.isActive(ctx) {
+		scope = resolveScope(ctx, cfg.scope)
+		if !scope.isActive(ctx) {
 			return createDiscardLogger()
 		}
-		// Add scope field to logger
-		baseLogger = baseLogger.With("ctxlog.scope", cfg.scope.name)
+		// Send logs to the scope's handler if configured
+		if handlerLogger := scope.handlerLogger(); handlerLogger != nil {
+			baseLogger = handlerLogger
+		}
 	}
 
-	// Check sampling
-	if cfg.sampling != nil {
-		var randVal float64
-		if cfg.fastRand {
-			randVal = fastRandFloat64()
-		} else {
-			randVal = cryptoRandFloat64()
-		}
-		if randVal > *cfg.sampling {
-			return createDiscardLogger()
-		}
+	// Apply attributes stored by WithAttrs
+	attrs, _ := ctx.Value(attrsKey).([]slog.Attr)
+	if scope != nil {
+		// Add attributes and scope field to logger and override minimum level if configured
+		level, hasLevel := scope.effectiveLevel(ctx)
+		baseLogger = scope.logger(baseLogger, attrs, level, hasLevel)
+	} else if len(attrs) > 0 {
+		baseLogger = slog.New(baseLogger.Handler().WithAttrs(attrs))
 	}
 
 	// Check condition
@@ -55,14 +54,67 @@ func From(ctx context.Context, options ...Option) *slog.Logger {
 		}
 	}
 
+	// Sample records when they are handled, so that Warn and above are kept
+	if cfg.sampling != nil || cfg.levelRate != nil {
+		baseLogger = slog.New(newSamplingHandler(ctx, baseLogger.Handler(), &cfg))
+	}
+
+	// Apply rate limiting per call site or key
+	if cfg.rateLimit != nil {
+		baseLogger = slog.New(&rateLimitHandler{
+			base:  baseLogger.Handler(),
+			key:   cfg.rateLimit.key,
+			limit: cfg.rateLimit.limit,
+			per:   cfg.rateLimit.per,
+		})
+	}
+
 	return baseLogger
 }
 
+// embeddedLogger returns the logger embedded by With, or slog.Default().
+func embeddedLogger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
 // With embeds a logger into the context and returns a new context.
 func With(ctx context.Context, logger *slog.Logger) context.Context {
 	return context.WithValue(ctx, loggerKey, logger)
 }
 
+// WithAttrs stores attributes in the context and returns a new context.
+// The attributes are applied by From on top of whichever logger it resolves,
+// including slog.Default(). Attributes are kept in insertion order and an
+// attribute with the same key as one set by a parent context replaces it.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+
+	existing, _ := ctx.Value(attrsKey).([]slog.Attr)
+	merged := make([]slog.Attr, len(existing), len(existing)+len(attrs))
+	copy(merged, existing)
+
+	for _, attr := range attrs {
+		replaced := false
+		for i := range merged {
+			if merged[i].Key == attr.Key {
+				merged[i] = attr
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, attr)
+		}
+	}
+
+	return context.WithValue(ctx, attrsKey, merged)
+}
+
 // randPool provides buffered cryptographically secure random numbers.
 type randPool struct {
 	mu     sync.Mutex
@@ -74,6 +126,9 @@ const (
 	randPoolSize         = 256 // Buffer size for random numbers
 	ieee754MantissaBits  = 53  // IEEE 754 double precision mantissa bits
 	ieee754MantissaShift = 11  // Bit shift to extract mantissa (64 - 53)
+
+	fnvOffset64 = 14695981039346656037 // FNV-1a 64-bit offset basis
+	fnvPrime64  = 1099511628211        // FNV-1a 64-bit prime
 )
 
 var globalRandPool = &randPool{ //nolint:gochecknoglobals // Required for performance buffering
@@ -123,3 +178,22 @@ func cryptoRandFloat64() float64 {
 	// Take the upper bits for IEEE 754 double precision mantissa
 	return float64(globalRandPool.getUint64()>>ieee754MantissaShift) * (1.0 / (1 << ieee754MantissaBits))
 }
+
+// hashFloat64 maps a key to a stable float64 between 0 and 1.
+// It uses FNV-1a followed by a 64-bit finalizer to spread similar keys evenly.
+func hashFloat64(key string) float64 {
+	h := uint64(fnvOffset64)
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= fnvPrime64
+	}
+
+	// splitmix64 finalizer
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+
+	return float64(h>>ieee754MantissaShift) * (1.0 / (1 << ieee754MantissaBits))
+}
@@ -1,8 +1,10 @@
 package ctxlog_test
 
 import (
+	"bytes"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/m-mizutani/ctxlog"
@@ -56,3 +58,42 @@ func TestMultipleOptions(t *testing.T) {
 		t.Error("Multiple options should fail when any condition is not met")
 	}
 }
+
+func TestWithAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	ctx = ctxlog.WithAttrs(ctx, slog.String("request_id", "req-1"), slog.String("tenant", "a"))
+	child := ctxlog.WithAttrs(ctx, slog.String("tenant", "b"), slog.Int("step", 2))
+
+	ctxlog.From(child).Info("child")
+	ctxlog.From(ctx).Info("parent")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d", len(lines))
+	}
+	if !strings.Contains(lines[0], `"request_id":"req-1","tenant":"b","step":2`) {
+		t.Errorf("Child attributes not applied in order: %s", lines[0])
+	}
+	if strings.Contains(lines[0], `"tenant":"a"`) {
+		t.Errorf("Parent attribute should be shadowed: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"request_id":"req-1","tenant":"a"`) || strings.Contains(lines[1], "step") {
+		t.Errorf("Parent context should not see child attributes: %s", lines[1])
+	}
+}
+
+func TestWithAttrsDefaultLogger(t *testing.T) {
+	var buf bytes.Buffer
+	original := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(original) })
+
+	ctx := ctxlog.WithAttrs(t.Context(), slog.String("request_id", "req-2"))
+	ctxlog.From(ctx).Info("hello")
+
+	if !strings.Contains(buf.String(), "request_id=req-2") {
+		t.Errorf("Attributes should be applied to default logger: %s", buf.String())
+	}
+}
@@ -1,7 +1,23 @@
 package ctxlog
 
-// Export unexported fields and methods for testing
+import (
+	"testing"
+	"time"
+)
 
-func (s *Scope) Parent() *Scope {
-	return s.parent
+// Export unexported functions for testing
+
+// ResetRateLimiters removes the state of all rate limiters so that tests
+// using the same call sites or keys are independent of previous runs.
+func ResetRateLimiters() {
+	resetRateLimiters()
+}
+
+// SetNow replaces the clock of scope deadlines with fn until the test ends.
+func SetNow(t testing.TB, fn func() time.Time) {
+	t.Helper()
+
+	orig := now
+	now = fn
+	t.Cleanup(func() { now = orig })
 }
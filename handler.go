@@ -2,7 +2,10 @@ package ctxlog
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"slices"
+	"strings"
 )
 
 // discardHandler creates a handler that discards all log records.
@@ -24,7 +27,182 @@ func (h *discardHandler) WithGroup(_ string) slog.Handler {
 	return h
 }
 
-// createDiscardLogger creates a logger that discards all output.
+// discardLogger is shared by all callers since loggers are immutable.
+var discardLogger = slog.New(&discardHandler{}) //nolint:gochecknoglobals // Avoids allocation per discarded logger
+
+// createDiscardLogger returns a logger that discards all output.
 func createDiscardLogger() *slog.Logger {
-	return slog.New(&discardHandler{})
+	return discardLogger
+}
+
+// levelHandler overrides the minimum level of the base handler.
+type levelHandler struct {
+	base  slog.Handler
+	level slog.Level
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+//nolint:gocritic // slog.Record must be passed by value per slog.Handler interface
+func (h *levelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.base.Handle(ctx, record)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{base: h.base.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{base: h.base.WithGroup(name), level: h.level}
+}
+
+// multiHandler sends each record to multiple handlers.
+type multiHandler struct {
+	handlers []slog.Handler
+}
+
+// Multi creates a handler that sends each record to all handlers enabled for
+// its level. Each handler receives its own clone of the record, and errors
+// are joined. Wrap handlers with FilterHandler to route records by level or scope.
+//
+// Example:
+//
+//	handler := ctxlog.Multi(
+//		slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
+//		ctxlog.FilterHandler(slog.NewTextHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug}),
+//			ctxlog.FilterScopes("database")),
+//	)
+func Multi(handlers ...slog.Handler) slog.Handler {
+	return &multiHandler{handlers: slices.Clone(handlers)}
+}
+
+func (h *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+//nolint:gocritic // slog.Record must be passed by value per slog.Handler interface
+func (h *multiHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, record.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return &multiHandler{handlers: handlers}
+}
+
+func (h *multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return &multiHandler{handlers: handlers}
+}
+
+// FilterOption configures a handler created by FilterHandler.
+type FilterOption func(*filterHandler)
+
+// FilterLevel passes only records at or above level.
+func FilterLevel(level slog.Leveler) FilterOption {
+	return func(h *filterHandler) {
+		h.level = level
+	}
+}
+
+// FilterScopes passes only records logged in the named scopes or their
+// children, identified by the ctxlog.scope attribute that From adds.
+func FilterScopes(names ...string) FilterOption {
+	return func(h *filterHandler) {
+		h.include = append(h.include, names...)
+	}
+}
+
+// FilterExcludeScopes drops records logged in the named scopes or their
+// children, e.g. to keep scoped logs routed elsewhere out of the main output.
+func FilterExcludeScopes(names ...string) FilterOption {
+	return func(h *filterHandler) {
+		h.exclude = append(h.exclude, names...)
+	}
+}
+
+// filterHandler passes records to the base handler by level and scope.
+type filterHandler struct {
+	base    slog.Handler
+	level   slog.Leveler
+	include []string
+	exclude []string
+	scope   string // scope name added by From
+}
+
+// FilterHandler creates a handler that passes records matching all options to
+// handler. It is typically used as a child of Multi.
+func FilterHandler(handler slog.Handler, options ...FilterOption) slog.Handler {
+	h := &filterHandler{base: handler}
+	for _, opt := range options {
+		opt(h)
+	}
+	return h
+}
+
+func (h *filterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.pass(level) && h.base.Enabled(ctx, level)
+}
+
+//nolint:gocritic // slog.Record must be passed by value per slog.Handler interface
+func (h *filterHandler) Handle(ctx context.Context, record slog.Record) error {
+	if !h.pass(record.Level) {
+		return nil
+	}
+	return h.base.Handle(ctx, record)
+}
+
+func (h *filterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.base = h.base.WithAttrs(attrs)
+	for _, attr := range attrs {
+		if attr.Key == scopeAttrKey {
+			clone.scope = attr.Value.String()
+		}
+	}
+	return &clone
+}
+
+func (h *filterHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.base = h.base.WithGroup(name)
+	return &clone
+}
+
+// pass reports whether records at level pass the level and scope filters.
+func (h *filterHandler) pass(level slog.Level) bool {
+	if h.level != nil && level < h.level.Level() {
+		return false
+	}
+	if len(h.include) > 0 && !slices.ContainsFunc(h.include, h.inScope) {
+		return false
+	}
+	return !slices.ContainsFunc(h.exclude, h.inScope)
+}
+
+// inScope reports whether the handler's scope is name or its child.
+func (h *filterHandler) inScope(name string) bool {
+	return h.scope == name || strings.HasPrefix(h.scope, name+".")
 }
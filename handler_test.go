@@ -0,0 +1,105 @@
+package ctxlog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/ctxlog"
+)
+
+func TestMulti(t *testing.T) {
+	var info, debug bytes.Buffer
+	handler := ctxlog.Multi(
+		slog.NewTextHandler(&info, &slog.HandlerOptions{Level: slog.LevelInfo}),
+		slog.NewTextHandler(&debug, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)
+	logger := slog.New(handler).With("service", "api").WithGroup("req")
+
+	if !handler.Enabled(t.Context(), slog.LevelDebug) {
+		t.Error("Expected Multi to be enabled if any handler is enabled")
+	}
+
+	logger.Debug("debug message", "id", 1)
+	logger.Info("info message", "id", 2)
+
+	if strings.Contains(info.String(), "debug message") ||
+		!strings.Contains(info.String(), "msg=\"info message\" service=api req.id=2") {
+		t.Errorf("Unexpected info output: %q", info.String())
+	}
+	if !strings.Contains(debug.String(), "msg=\"debug message\" service=api req.id=1") ||
+		!strings.Contains(debug.String(), "info message") {
+		t.Errorf("Unexpected debug output: %q", debug.String())
+	}
+
+	filtered := ctxlog.FilterHandler(slog.NewTextHandler(&info, nil), ctxlog.FilterLevel(slog.LevelError))
+	if ctxlog.Multi(filtered).Enabled(t.Context(), slog.LevelWarn) {
+		t.Error("Expected Multi to be disabled if no handler is enabled")
+	}
+}
+
+func TestMultiErrors(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+	var output bytes.Buffer
+
+	handler := ctxlog.Multi(
+		&failingHandler{err: errFirst},
+		slog.NewTextHandler(&output, nil),
+		&failingHandler{err: errSecond},
+	)
+
+	err := handler.Handle(t.Context(), slog.NewRecord(time.Now(), slog.LevelInfo, "message", 0))
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Errorf("Expected joined errors, got %v", err)
+	}
+	if !strings.Contains(output.String(), "msg=message") {
+		t.Errorf("Expected other handlers to still receive the record, got %q", output.String())
+	}
+}
+
+func TestFilterHandlerScopes(t *testing.T) {
+	scope := ctxlog.NewScope("filter_database")
+	child := scope.NewChild("query")
+
+	var main, database bytes.Buffer
+	handler := ctxlog.Multi(
+		ctxlog.FilterHandler(slog.NewTextHandler(&main, nil), ctxlog.FilterExcludeScopes("filter_database")),
+		ctxlog.FilterHandler(slog.NewTextHandler(&database, &slog.HandlerOptions{Level: slog.LevelDebug}),
+			ctxlog.FilterScopes("filter_database")),
+	)
+
+	ctx := ctxlog.With(t.Context(), slog.New(handler))
+	ctx = ctxlog.EnableScope(ctx, scope)
+
+	ctxlog.From(ctx).Info("request")
+	ctxlog.From(ctx, scope).Debug("connect")
+	ctxlog.From(ctx, child).Debug("select")
+
+	if !strings.Contains(main.String(), "request") || strings.Contains(main.String(), "connect") {
+		t.Errorf("Expected main output without scoped logs, got %q", main.String())
+	}
+	if strings.Contains(database.String(), "request") ||
+		!strings.Contains(database.String(), "msg=connect ctxlog.scope=filter_database") ||
+		!strings.Contains(database.String(), "msg=select ctxlog.scope=filter_database.query") {
+		t.Errorf("Expected database output with scope and child logs only, got %q", database.String())
+	}
+}
+
+// failingHandler returns err from Handle for testing.
+type failingHandler struct {
+	err error
+}
+
+func (h *failingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+//nolint:gocritic // slog.Record must be passed by value per slog.Handler interface
+func (h *failingHandler) Handle(context.Context, slog.Record) error { return h.err }
+
+func (h *failingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *failingHandler) WithGroup(string) slog.Handler { return h }
@@ -1,5 +1,13 @@
 package ctxlog
 
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+)
+
 // Option represents configuration options for logger creation
 type Option interface {
 	apply(cfg *config)
@@ -9,8 +17,29 @@ type Option interface {
 type config struct {
 	scope     *Scope
 	sampling  *float64
+	sampleKey func(ctx context.Context) string
 	condition func() bool
 	fastRand  bool
+	rateLimit *rateLimitOption
+	levelRate []levelRate
+}
+
+// newConfig builds config from options. Options consisting only of scopes are
+// applied without heap allocation to keep From allocation-free on hot paths.
+func newConfig(options []Option) config {
+	var scope *Scope
+	for _, opt := range options {
+		s, ok := opt.(*Scope)
+		if !ok {
+			cfg := &config{}
+			for _, opt := range options {
+				opt.apply(cfg)
+			}
+			return *cfg
+		}
+		scope = s
+	}
+	return config{scope: scope}
 }
 
 // samplingOption implements Option interface for sampling
@@ -22,11 +51,74 @@ func (s samplingOption) apply(c *config) {
 	c.sampling = &s.rate
 }
 
-// WithSampling creates an option to enable probabilistic logging
+// WithSampling creates an option to enable probabilistic logging of records
+// below Warn. The decision is made once per From call, so the returned logger
+// keeps or drops all of them, while Warn and above always pass. A decision
+// pinned by Sample or SetSampled takes precedence over the rate.
 func WithSampling(rate float64) Option {
 	return samplingOption{rate: rate}
 }
 
+// sampleKeyOption implements Option interface for key-based sampling
+type sampleKeyOption struct {
+	rate float64
+	key  func(ctx context.Context) string
+}
+
+func (s sampleKeyOption) apply(c *config) {
+	c.sampling = &s.rate
+	c.sampleKey = s.key
+}
+
+// WithSampleKey creates an option to enable deterministic sampling by key.
+// The key is hashed to make a stable keep/drop decision, so all log lines
+// with the same key (e.g. request ID or trace ID) are either all kept or all
+// dropped, across processes. The same key and rate always produce the same result.
+// Like WithSampling, Warn and above always pass.
+func WithSampleKey(key string, rate float64) Option {
+	return sampleKeyOption{
+		rate: rate,
+		key:  func(context.Context) string { return key },
+	}
+}
+
+// WithSampleKeyFunc creates an option to enable deterministic sampling by a key
+// extracted from the context. See WithSampleKey for the sampling behavior.
+func WithSampleKeyFunc(keyFunc func(ctx context.Context) string, rate float64) Option {
+	return sampleKeyOption{rate: rate, key: keyFunc}
+}
+
+// levelSamplingOption implements Option interface for level-aware sampling
+type levelSamplingOption struct {
+	rates []levelRate
+}
+
+func (l levelSamplingOption) apply(c *config) {
+	c.levelRate = l.rates
+}
+
+// WithLevelSampling creates an option to sample records by their level.
+// A rate applies to its level and custom levels above it up to the next
+// configured level, e.g. a rate for Info also applies to LevelInfo+2. Warn and
+// above pass unless a rate is configured at Warn or above, and levels below
+// the lowest configured level always pass. Rates take precedence over
+// WithSampling, and a decision pinned by Sample or SetSampled over both.
+//
+// Example:
+//
+//	logger := ctxlog.From(ctx, ctxlog.WithLevelSampling(map[slog.Level]float64{
+//	    slog.LevelDebug: 0.01,
+//	    slog.LevelInfo:  0.1,
+//	}))
+func WithLevelSampling(rates map[slog.Level]float64) Option {
+	sorted := make([]levelRate, 0, len(rates))
+	for level, rate := range rates {
+		sorted = append(sorted, levelRate{level: level, rate: rate})
+	}
+	slices.SortFunc(sorted, func(a, b levelRate) int { return cmp.Compare(a.level, b.level) })
+	return levelSamplingOption{rates: sorted}
+}
+
 // conditionOption implements Option interface for conditional logging
 type conditionOption struct {
 	condition func() bool
@@ -41,6 +133,39 @@ func WithCond(condition func() bool) Option {
 	return conditionOption{condition: condition}
 }
 
+// rateLimitOption implements Option interface for rate-limited logging
+type rateLimitOption struct {
+	key   string
+	limit int
+	per   time.Duration
+}
+
+func (r rateLimitOption) apply(c *config) {
+	// A bucket without capacity or refill would drop records forever
+	if r.limit <= 0 || r.per <= 0 {
+		c.rateLimit = nil
+		return
+	}
+	c.rateLimit = &r
+}
+
+// WithRateLimit creates an option to limit records of each call site with a
+// token bucket of n records refilled at n records per interval, so a burst of
+// n records passes and the rest are dropped. One interval after the first
+// dropped record, a summary record "suppressed N messages" is emitted with the
+// count in the "ctxlog.suppressed" attribute, even if no further records arrive.
+// If n or per is not positive, rate limiting is disabled.
+func WithRateLimit(n int, per time.Duration) Option {
+	return rateLimitOption{limit: n, per: per}
+}
+
+// WithRateLimitKey works like WithRateLimit but shares the limit among all
+// loggers created with the same key instead of limiting per call site. If the
+// key is used with a different n or interval, the most recent one applies.
+func WithRateLimitKey(key string, n int, per time.Duration) Option {
+	return rateLimitOption{key: key, limit: n, per: per}
+}
+
 // fastRandOption implements Option interface for fast random number generation
 type fastRandOption struct{}
 
@@ -1,6 +1,8 @@
 package ctxlog_test
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"testing"
 
@@ -38,3 +40,53 @@ func TestConditionalLogging(t *testing.T) {
 		t.Error("Conditional logging should allow when condition is true")
 	}
 }
+
+func TestSampleKey(t *testing.T) {
+	ctx := t.Context()
+
+	// Same key and rate should always produce the same decision
+	for _, key := range []string{"req-1", "req-2", "user-42", "trace-abc"} {
+		first := ctxlog.From(ctx, ctxlog.WithSampleKey(key, 0.5)).Enabled(ctx, slog.LevelInfo)
+		for range 10 {
+			if got := ctxlog.From(ctx, ctxlog.WithSampleKey(key, 0.5)).Enabled(ctx, slog.LevelInfo); got != first {
+				t.Errorf("Sampling decision for key %q should be stable", key)
+			}
+		}
+	}
+
+	// Rate 0 and 1 behave like WithSampling
+	if ctxlog.From(ctx, ctxlog.WithSampleKey("req-1", 0.0)).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Key sampling with rate 0 should always discard")
+	}
+	if !ctxlog.From(ctx, ctxlog.WithSampleKey("req-1", 1.0)).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Key sampling with rate 1 should never discard")
+	}
+
+	// Roughly rate * N keys should be kept
+	kept := 0
+	for i := range 10000 {
+		if ctxlog.From(ctx, ctxlog.WithSampleKey(fmt.Sprintf("req-%d", i), 0.3)).Enabled(ctx, slog.LevelInfo) {
+			kept++
+		}
+	}
+	if kept < 2700 || kept > 3300 {
+		t.Errorf("Expected about 3000 kept keys, got %d", kept)
+	}
+}
+
+func TestSampleKeyFunc(t *testing.T) {
+	type keyType struct{}
+	keyFunc := func(ctx context.Context) string {
+		v, _ := ctx.Value(keyType{}).(string)
+		return v
+	}
+
+	for i := range 100 {
+		ctx := context.WithValue(t.Context(), keyType{}, fmt.Sprintf("req-%d", i))
+		byFunc := ctxlog.From(ctx, ctxlog.WithSampleKeyFunc(keyFunc, 0.5)).Enabled(ctx, slog.LevelInfo)
+		byKey := ctxlog.From(ctx, ctxlog.WithSampleKey(fmt.Sprintf("req-%d", i), 0.5)).Enabled(ctx, slog.LevelInfo)
+		if byFunc != byKey {
+			t.Errorf("Key function and static key should produce the same decision for req-%d", i)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package ctxlog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// rateLimiters holds a limiter per call site or explicit key.
+var rateLimiters sync.Map //nolint:gochecknoglobals // Required for rate limiter registry
+
+// rateLimitKey identifies a limiter by explicit key or by call site.
+type rateLimitKey struct {
+	key string
+	pc  uintptr
+}
+
+// rateLimiter is a token bucket holding up to limit tokens, refilled at limit
+// tokens per interval. Records without a token are counted and reported by a
+// summary once per interval.
+type rateLimiter struct {
+	mu         sync.Mutex
+	limit      int
+	per        time.Duration
+	tokens     float64
+	last       time.Time
+	suppressed int
+	pending    bool        // a summary is scheduled for the suppressed records
+	summary    *time.Timer // timer of the pending summary
+}
+
+// allow reports whether a record may pass at now. limit and per replace the
+// limiter's configuration if they differ, so the most recent option applies.
+// The second result reports that the record is the first suppressed one since
+// the last summary, and the caller must schedule the next summary.
+func (rl *rateLimiter) allow(now time.Time, limit int, per time.Duration) (bool, bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.limit != limit || rl.per != per {
+		rl.limit = limit
+		rl.per = per
+		rl.tokens = min(rl.tokens, float64(limit))
+	}
+
+	// Refill tokens for the time elapsed since the last record
+	if !rl.last.IsZero() {
+		rl.tokens += float64(rl.limit) * float64(now.Sub(rl.last)) / float64(rl.per)
+	}
+	rl.tokens = min(rl.tokens, float64(rl.limit))
+	rl.last = now
+
+	if rl.tokens >= 1 {
+		rl.tokens--
+		return true, false
+	}
+
+	rl.suppressed++
+	if rl.pending {
+		return false, false
+	}
+	rl.pending = true
+	return false, true
+}
+
+// scheduleSummary calls emit with the number of suppressed records after one
+// interval, so the count is reported even if no further records arrive.
+func (rl *rateLimiter) scheduleSummary(emit func(count int)) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.summary = time.AfterFunc(rl.per, func() {
+		rl.mu.Lock()
+		suppressed := rl.suppressed
+		rl.suppressed = 0
+		rl.pending = false
+		rl.summary = nil
+		rl.mu.Unlock()
+
+		if suppressed > 0 {
+			emit(suppressed)
+		}
+	})
+}
+
+// newRateLimiter creates a limiter with a full bucket.
+func newRateLimiter(limit int, per time.Duration) *rateLimiter {
+	return &rateLimiter{limit: limit, per: per, tokens: float64(limit)}
+}
+
+// resetRateLimiters removes all limiters and their pending summaries.
+func resetRateLimiters() {
+	rateLimiters.Range(func(key, value any) bool {
+		rl, _ := value.(*rateLimiter)
+		rl.mu.Lock()
+		if rl.summary != nil {
+			rl.summary.Stop()
+		}
+		rl.mu.Unlock()
+		rateLimiters.Delete(key)
+		return true
+	})
+}
+
+// rateLimitHandler drops records exceeding the rate limit of their limiter.
+type rateLimitHandler struct {
+	base  slog.Handler
+	key   string
+	limit int
+	per   time.Duration
+}
+
+func (h *rateLimitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.base.Enabled(ctx, level)
+}
+
+//nolint:gocritic // slog.Record must be passed by value per slog.Handler interface
+func (h *rateLimitHandler) Handle(ctx context.Context, record slog.Record) error {
+	key := rateLimitKey{key: h.key}
+	if h.key == "" {
+		key.pc = record.PC
+	}
+
+	limiter, ok := rateLimiters.Load(key)
+	if !ok {
+		limiter, _ = rateLimiters.LoadOrStore(key, newRateLimiter(h.limit, h.per))
+	}
+
+	rl, _ := limiter.(*rateLimiter)
+	allowed, schedule := rl.allow(time.Now(), h.limit, h.per)
+	if schedule {
+		// The summary is emitted after the record's context may be canceled
+		summaryCtx := context.WithoutCancel(ctx)
+		level, pc := record.Level, record.PC
+		rl.scheduleSummary(func(count int) {
+			summary := slog.NewRecord(time.Now(), level, fmt.Sprintf("suppressed %d messages", count), pc)
+			summary.AddAttrs(slog.Int("ctxlog.suppressed", count))
+			_ = h.base.Handle(summaryCtx, summary)
+		})
+	}
+
+	if !allowed {
+		return nil
+	}
+	return h.base.Handle(ctx, record)
+}
+
+func (h *rateLimitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &rateLimitHandler{base: h.base.WithAttrs(attrs), key: h.key, limit: h.limit, per: h.per}
+}
+
+func (h *rateLimitHandler) WithGroup(name string) slog.Handler {
+	return &rateLimitHandler{base: h.base.WithGroup(name), key: h.key, limit: h.limit, per: h.per}
+}
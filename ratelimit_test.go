@@ -0,0 +1,181 @@
+package ctxlog_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m-mizutani/ctxlog"
+)
+
+func TestRateLimit(t *testing.T) {
+	ctxlog.ResetRateLimiters()
+
+	out := &lockedWriter{w: &bytes.Buffer{}, mu: &sync.Mutex{}}
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(out, nil)))
+
+	// All records are logged from one call site
+	logHot := func() {
+		ctxlog.From(ctx, ctxlog.WithRateLimit(3, 50*time.Millisecond)).Info("hot loop")
+	}
+
+	for range 10 {
+		logHot()
+	}
+	if n := strings.Count(out.String(), "hot loop"); n != 3 {
+		t.Errorf("Expected 3 records in first burst, got %d", n)
+	}
+
+	waitForOutput(t, out, `msg="suppressed 7 messages"`)
+	if !strings.Contains(out.String(), "ctxlog.suppressed=7") {
+		t.Errorf("Expected summary record for suppressed messages: %s", out.String())
+	}
+
+	// Records pass again once the bucket is refilled
+	deadline := time.Now().Add(5 * time.Second)
+	for strings.Count(out.String(), "hot loop") < 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected record to pass after refill, got %q", out.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+		logHot()
+	}
+}
+
+// waitForOutput waits until out contains s.
+func waitForOutput(t *testing.T, out *lockedWriter, s string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), s) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %q in output, got %q", s, out.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRateLimitSummaryWithoutFurtherRecords(t *testing.T) {
+	ctxlog.ResetRateLimiters()
+
+	out := &lockedWriter{w: &bytes.Buffer{}, mu: &sync.Mutex{}}
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(out, nil)))
+
+	for range 5 {
+		ctxlog.From(ctx, ctxlog.WithRateLimitKey("summary-flush", 1, 20*time.Millisecond)).Info("burst")
+	}
+
+	waitForOutput(t, out, `msg="suppressed 4 messages"`)
+}
+
+func TestRateLimitInvalidArguments(t *testing.T) {
+	ctxlog.ResetRateLimiters()
+
+	var buf bytes.Buffer
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(&buf, nil)))
+
+	// Non-positive arguments disable rate limiting instead of dropping records
+	for _, opt := range []ctxlog.Option{
+		ctxlog.WithRateLimitKey("zero-per", 2, 0),
+		ctxlog.WithRateLimitKey("zero-limit", 0, time.Second),
+		ctxlog.WithRateLimit(-1, time.Second),
+	} {
+		buf.Reset()
+		for range 5 {
+			ctxlog.From(ctx, opt).Info("unlimited")
+		}
+		if n := strings.Count(buf.String(), "unlimited"); n != 5 {
+			t.Errorf("Expected all records to pass, got %d", n)
+		}
+		if strings.Contains(buf.String(), "suppressed") {
+			t.Errorf("Expected no summary, got %q", buf.String())
+		}
+	}
+}
+
+func TestRateLimitKeyReconfigured(t *testing.T) {
+	ctxlog.ResetRateLimiters()
+
+	var buf bytes.Buffer
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(&buf, nil)))
+
+	for range 3 {
+		ctxlog.From(ctx, ctxlog.WithRateLimitKey("reconfigured", 1, time.Hour)).Info("first")
+	}
+	if n := strings.Count(buf.String(), "first"); n != 1 {
+		t.Errorf("Expected 1 record with first config, got %d", n)
+	}
+
+	// A larger limit raises the bucket capacity; tokens refill from there
+	for range 3 {
+		ctxlog.From(ctx, ctxlog.WithRateLimitKey("reconfigured", 100, time.Nanosecond)).Info("second")
+	}
+	if n := strings.Count(buf.String(), "second"); n != 3 {
+		t.Errorf("Expected new config to apply, got %d records", n)
+	}
+}
+
+func TestRateLimitPerCallSite(t *testing.T) {
+	ctxlog.ResetRateLimiters()
+
+	var buf bytes.Buffer
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(&buf, nil)))
+
+	for range 5 {
+		ctxlog.From(ctx, ctxlog.WithRateLimit(1, time.Hour)).Info("site A")
+		ctxlog.From(ctx, ctxlog.WithRateLimit(1, time.Hour)).Info("site B")
+	}
+
+	if n := strings.Count(buf.String(), "site A"); n != 1 {
+		t.Errorf("Expected 1 record from site A, got %d", n)
+	}
+	if n := strings.Count(buf.String(), "site B"); n != 1 {
+		t.Errorf("Expected 1 record from site B, got %d", n)
+	}
+}
+
+func TestRateLimitKeyConcurrent(t *testing.T) {
+	ctxlog.ResetRateLimiters()
+
+	var buf bytes.Buffer
+	var mu sync.Mutex
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(&lockedWriter{w: &buf, mu: &mu}, nil)))
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				ctxlog.From(ctx, ctxlog.WithRateLimitKey("test-rate-limit-key", 10, time.Hour)).Info("shared")
+			}
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if n := strings.Count(buf.String(), "shared"); n != 10 {
+		t.Errorf("Expected 10 records for shared key, got %d", n)
+	}
+}
+
+type lockedWriter struct {
+	w  *bytes.Buffer
+	mu *sync.Mutex
+}
+
+func (lw *lockedWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.w.Write(p)
+}
+
+func (lw *lockedWriter) String() string {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.w.String()
+}
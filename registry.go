@@ -0,0 +1,286 @@
+package ctxlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrScopeConflict is returned when a scope is registered again with
+// different options or a different parent.
+var ErrScopeConflict = errors.New("scope conflict")
+
+// Registry is a namespace of scopes. Scopes are unique by name within a
+// registry. The package-level functions such as NewScope and ConfigureScopes
+// use the default registry; libraries can create their own registry with
+// NewRegistry to avoid name collisions.
+type Registry struct {
+	scopes map[string]*Scope
+	rules  []scopeRule
+	strict bool
+	mu     sync.RWMutex
+}
+
+// defaultRegistry backs the package-level scope functions.
+var defaultRegistry = NewRegistry() //nolint:gochecknoglobals // Required for default scope registry
+
+type ctxRegistryKey struct{}
+
+var registryKey = ctxRegistryKey{} //nolint:gochecknoglobals // Required for context key
+
+// NewRegistry creates an empty scope registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		scopes: make(map[string]*Scope),
+	}
+}
+
+// DefaultRegistry returns the registry used by the package-level functions.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// SetStrict enables or disables strict mode. In strict mode, NewScope and
+// NewChild panic when a scope is registered again with different options,
+// instead of silently returning the existing scope.
+func (r *Registry) SetStrict(strict bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.strict = strict
+}
+
+// isStrict reports whether strict mode is enabled.
+func (r *Registry) isStrict() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.strict
+}
+
+// NewScope creates a new scope in the registry. See the package-level
+// NewScope for details.
+func (r *Registry) NewScope(name string, options ...ScopeOption) *Scope {
+	scope, err := r.register(name, nil, options)
+	if err != nil && r.isStrict() {
+		panic(err)
+	}
+	return scope
+}
+
+// Register creates a new scope in the registry, or returns the existing scope
+// with the same name. If the existing scope was registered with different
+// options, it is returned with an error wrapping ErrScopeConflict.
+func (r *Registry) Register(name string, options ...ScopeOption) (*Scope, error) {
+	return r.register(name, nil, options)
+}
+
+// register creates or returns the scope with the given name. If parent is not
+// nil, the existing scope must have the same parent.
+func (r *Registry) register(name string, parent *Scope, options []ScopeOption) (*Scope, error) {
+	cfg := &scopeConfig{}
+	for _, opt := range options {
+		opt(cfg)
+	}
+	return r.registerConfig(name, parent, cfg)
+}
+
+// registerConfig creates or returns the scope with the given name and configuration.
+func (r *Registry) registerConfig(name string, parent *Scope, cfg *scopeConfig) (*Scope, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, exists := r.scopes[name]; exists {
+		if parent != nil && existing.parent != parent {
+			return existing, fmt.Errorf("%w: %q is already registered with a different parent", ErrScopeConflict, name)
+		}
+		if !existing.config().equal(cfg) {
+			return existing, fmt.Errorf("%w: %q is already registered with different options", ErrScopeConflict, name)
+		}
+		return existing, nil
+	}
+
+	scope := &Scope{
+		registry: r,
+		name:     name,
+		envConds: cfg.envConds,
+		funcs:    cfg.funcs,
+		level:    cfg.level,
+		parent:   parent,
+	}
+	if cfg.handler != nil {
+		scope.handler = slog.New(cfg.handler)
+	}
+	r.scopes[name] = scope
+
+	if parent != nil {
+		parent.mu.Lock()
+		parent.children = append(parent.children, scope)
+		parent.mu.Unlock()
+	}
+
+	// Apply rules configured before the scope was created
+	for _, rule := range r.rules {
+		if rule.match(name) {
+			rule.apply(scope)
+		}
+	}
+
+	return scope, nil
+}
+
+// Lookup returns the scope with the given name.
+func (r *Registry) Lookup(name string) (*Scope, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scope, ok := r.scopes[name]
+	return scope, ok
+}
+
+// Scopes returns all scopes in the registry sorted by name.
+func (r *Registry) Scopes() []*Scope {
+	r.mu.RLock()
+	scopes := make([]*Scope, 0, len(r.scopes))
+	for _, scope := range r.scopes {
+		scopes = append(scopes, scope)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(scopes, func(i, j int) bool { return scopes[i].name < scopes[j].name })
+	return scopes
+}
+
+// Refresh re-evaluates environment variable conditions of all scopes in the
+// registry. See RefreshScopes.
+func (r *Registry) Refresh() {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, scope := range r.scopes {
+		scope.loadEnv()
+	}
+}
+
+// config returns the configuration the scope was created with.
+func (s *Scope) config() *scopeConfig {
+	cfg := &scopeConfig{
+		envConds: s.envConds,
+		funcs:    s.funcs,
+		level:    s.level,
+	}
+	if s.handler != nil {
+		cfg.handler = s.handler.Handler()
+	}
+	return cfg
+}
+
+// adopt returns the scope in the registry with the same name as the given
+// scope. If the scope belongs to another registry and is not registered here,
+// a scope with the same configuration and hierarchy is created.
+func (r *Registry) adopt(scope *Scope) *Scope {
+	if scope.registry == r {
+		return scope
+	}
+	if existing, ok := r.Lookup(scope.name); ok {
+		return existing
+	}
+
+	var parent *Scope
+	if scope.parent != nil {
+		parent = r.adopt(scope.parent)
+	}
+
+	// Conflicts are ignored since the scope with the name is returned either way
+	adopted, _ := r.registerConfig(scope.name, parent, scope.config())
+	return adopted
+}
+
+// adoptAll returns the scopes in the registry corresponding to the given scopes.
+func (r *Registry) adoptAll(scopes []*Scope) []*Scope {
+	adopted := make([]*Scope, len(scopes))
+	for i, scope := range scopes {
+		adopted[i] = r.adopt(scope)
+	}
+	return adopted
+}
+
+// EnableGlobal enables the given scopes in the registry. Scopes of other
+// registries, such as package-level scopes, are resolved by name, so a test
+// can enable them in its own registry attached with WithRegistry.
+func (r *Registry) EnableGlobal(scopes ...*Scope) {
+	EnableScopeGlobal(r.adoptAll(scopes)...)
+}
+
+// EnableGlobalFor enables the given scopes in the registry until the duration
+// elapses. See EnableScopeGlobalFor.
+func (r *Registry) EnableGlobalFor(d time.Duration, scopes ...*Scope) {
+	EnableScopeGlobalFor(d, r.adoptAll(scopes)...)
+}
+
+// DisableGlobal removes global enablement of the given scopes in the registry.
+// See DisableScopeGlobal.
+func (r *Registry) DisableGlobal(scopes ...*Scope) {
+	DisableScopeGlobal(r.adoptAll(scopes)...)
+}
+
+// DenyGlobal denies the given scopes in the registry. See DenyScopeGlobal.
+func (r *Registry) DenyGlobal(scopes ...*Scope) {
+	DenyScopeGlobal(r.adoptAll(scopes)...)
+}
+
+// ResetGlobal removes enablement and deny of the given scopes in the registry.
+// See ResetScopeGlobal.
+func (r *Registry) ResetGlobal(scopes ...*Scope) {
+	ResetScopeGlobal(r.adoptAll(scopes)...)
+}
+
+// GlobalEnabledScopes returns the scopes enabled in the registry.
+func (r *Registry) GlobalEnabledScopes() []*Scope {
+	candidates := make([]*Scope, 0)
+	for _, scope := range r.Scopes() {
+		if scope.flags.Load()&scopeFlagAllow != 0 {
+			candidates = append(candidates, scope)
+		}
+	}
+
+	// Check expiration after collecting since it may call OnScopeExpired hooks
+	scopes := candidates[:0]
+	for _, scope := range candidates {
+		if !scope.expireGlobal() {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// WithRegistry returns a new context with the registry attached. From resolves
+// scopes against the attached registry by name, so scope state of the registry
+// applies instead of the state of the registry the scope was created in.
+// This lets parallel tests enable and disable scopes without interfering.
+//
+// Example:
+//
+//	func TestHandler(t *testing.T) {
+//	    t.Parallel()
+//	    registry := ctxlog.NewRegistry()
+//	    registry.EnableGlobal(apiScope)
+//	    ctx := ctxlog.WithRegistry(t.Context(), registry)
+//	    ctxlog.From(ctx, apiScope).Info("enabled only in this test")
+//	}
+func WithRegistry(ctx context.Context, registry *Registry) context.Context {
+	return context.WithValue(ctx, registryKey, registry)
+}
+
+// resolveScope returns the scope to evaluate for the context, resolving it
+// against the registry attached by WithRegistry.
+func resolveScope(ctx context.Context, scope *Scope) *Scope {
+	if registry, ok := ctx.Value(registryKey).(*Registry); ok && registry != scope.registry {
+		return registry.adopt(scope)
+	}
+	return scope
+}
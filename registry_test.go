@@ -0,0 +1,207 @@
+package ctxlog_test
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/m-mizutani/ctxlog"
+)
+
+func TestRegisterConflict(t *testing.T) {
+	scope, err := ctxlog.Register("register-conflict", ctxlog.EnabledBy("REGISTER_A"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Same options are not a conflict
+	same, err := ctxlog.Register("register-conflict", ctxlog.EnabledBy("REGISTER_A"))
+	if err != nil || same != scope {
+		t.Errorf("Registering with same options should return existing scope, got %v", err)
+	}
+
+	// Different options are reported
+	for _, opts := range [][]ctxlog.ScopeOption{
+		{ctxlog.EnabledBy("REGISTER_B")},
+		{ctxlog.EnabledByTruthy("REGISTER_A")},
+		{ctxlog.EnabledBy("REGISTER_A"), ctxlog.WithLevel(slog.LevelDebug)},
+		{ctxlog.EnabledBy("REGISTER_A"), ctxlog.WithHandler(slog.DiscardHandler)},
+		nil,
+	} {
+		existing, err := ctxlog.Register("register-conflict", opts...)
+		if !errors.Is(err, ctxlog.ErrScopeConflict) {
+			t.Errorf("Expected ErrScopeConflict, got %v", err)
+		}
+		if existing != scope {
+			t.Error("Existing scope should be returned on conflict")
+		}
+	}
+}
+
+func TestRegisterHandlerConflict(t *testing.T) {
+	registry := ctxlog.NewRegistry()
+	first := slog.NewTextHandler(io.Discard, nil)
+	scope := registry.NewScope("handler", ctxlog.WithHandler(first))
+
+	// Same handler is not a conflict
+	same, err := registry.Register("handler", ctxlog.WithHandler(first))
+	if err != nil || same != scope {
+		t.Errorf("Registering with same handler should return existing scope, got %v", err)
+	}
+
+	// Different handler of the same type is reported
+	existing, err := registry.Register("handler", ctxlog.WithHandler(slog.NewTextHandler(io.Discard, nil)))
+	if !errors.Is(err, ctxlog.ErrScopeConflict) {
+		t.Errorf("Expected ErrScopeConflict, got %v", err)
+	}
+	if existing != scope {
+		t.Error("Existing scope should be returned on conflict")
+	}
+}
+
+// taggedHandler is a comparable handler type whose value may not be comparable.
+type taggedHandler struct {
+	slog.Handler
+	tags any
+}
+
+func TestRegisterUncomparableHandler(t *testing.T) {
+	registry := ctxlog.NewRegistry()
+	handler := taggedHandler{Handler: slog.DiscardHandler, tags: []string{"a"}}
+	scope := registry.NewScope("uncomparable", ctxlog.WithHandler(handler))
+
+	// Handlers that cannot be compared are matched by type, without panicking
+	same, err := registry.Register("uncomparable", ctxlog.WithHandler(handler))
+	if err != nil || same != scope {
+		t.Errorf("Registering with same handler type should return existing scope, got %v", err)
+	}
+}
+
+func TestRegistryStrict(t *testing.T) {
+	registry := ctxlog.NewRegistry()
+	registry.SetStrict(true)
+
+	scope := registry.NewScope("strict", ctxlog.EnabledBy("STRICT_A"))
+	if registry.NewScope("strict", ctxlog.EnabledBy("STRICT_A")) != scope {
+		t.Error("Same options should not panic in strict mode")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic on conflict in strict mode")
+		}
+	}()
+	registry.NewScope("strict", ctxlog.EnabledBy("STRICT_B"))
+}
+
+func TestNewChildIdempotent(t *testing.T) {
+	parent := ctxlog.NewScope("idempotent-parent")
+	child1 := parent.NewChild("child", ctxlog.EnabledBy("IDEMPOTENT_CHILD"))
+	child2 := parent.NewChild("child", ctxlog.EnabledBy("IDEMPOTENT_CHILD"))
+
+	if child1 != child2 {
+		t.Error("NewChild should return existing child")
+	}
+	if children := parent.Children(); len(children) != 1 {
+		t.Errorf("Child should be registered once, got %d", len(children))
+	}
+
+	// A root scope registered with the child's full name is a conflict
+	registry := ctxlog.NewRegistry()
+	registry.SetStrict(true)
+	strictParent := registry.NewScope("strict-parent")
+	registry.NewScope("strict-parent.child")
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic on parent conflict in strict mode")
+		}
+	}()
+	strictParent.NewChild("child")
+}
+
+func TestRegistryIsolation(t *testing.T) {
+	registry := ctxlog.NewRegistry()
+	scope := registry.NewScope("isolated")
+	global := ctxlog.NewScope("isolated")
+
+	if scope == global {
+		t.Error("Registries should have separate namespaces")
+	}
+	if found, ok := registry.Lookup("isolated"); !ok || found != scope {
+		t.Error("Lookup should find scope in its registry")
+	}
+	if scopes := registry.Scopes(); len(scopes) != 1 {
+		t.Errorf("Expected 1 scope in registry, got %d", len(scopes))
+	}
+
+	child := scope.NewChild("child")
+	if _, ok := registry.Lookup("isolated.child"); !ok {
+		t.Error("Child should be registered in the parent's registry")
+	}
+	if _, ok := ctxlog.LookupScope("isolated.child"); ok {
+		t.Error("Child should not be registered in the default registry")
+	}
+	if child.Parent() != scope {
+		t.Error("Child parent not set")
+	}
+}
+
+func TestRegistryGlobalState(t *testing.T) {
+	registry := ctxlog.NewRegistry()
+	scope := registry.NewScope("registry-state")
+	ctx := t.Context()
+
+	registry.EnableGlobal(scope)
+	if !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be enabled in its registry")
+	}
+	if enabled := registry.GlobalEnabledScopes(); len(enabled) != 1 || enabled[0] != scope {
+		t.Errorf("Unexpected enabled scopes: %v", enabled)
+	}
+
+	registry.DisableGlobal(scope)
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be disabled in its registry")
+	}
+
+	registry.DenyGlobal(scope)
+	if a := scope.Explain(ctx); a.Rule != ctxlog.ScopeRuleGlobalDeny {
+		t.Errorf("Scope should be denied in its registry, got %v", a)
+	}
+}
+
+func TestWithRegistryParallel(t *testing.T) {
+	apiScope := ctxlog.NewScope("registry-parallel-api")
+	userScope := apiScope.NewChild("user")
+
+	for _, enabled := range []bool{true, false} {
+		t.Run(map[bool]string{true: "enabled", false: "disabled"}[enabled], func(t *testing.T) {
+			t.Parallel()
+
+			registry := ctxlog.NewRegistry()
+			ctx := ctxlog.WithRegistry(t.Context(), registry)
+
+			for range 100 {
+				if enabled {
+					registry.EnableGlobal(apiScope)
+				} else {
+					registry.DisableGlobal(apiScope)
+				}
+
+				if got := ctxlog.From(ctx, userScope).Enabled(ctx, slog.LevelInfo); got != enabled {
+					t.Fatalf("Expected child scope active=%v in attached registry, got %v", enabled, got)
+				}
+				if got := userScope.Active(ctx); got != enabled {
+					t.Fatalf("Expected Active=%v in attached registry, got %v", enabled, got)
+				}
+			}
+		})
+	}
+
+	// Default registry is not affected
+	if ctxlog.From(t.Context(), apiScope).Enabled(t.Context(), slog.LevelInfo) {
+		t.Error("Default registry should not be affected by other registries")
+	}
+}
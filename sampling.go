@@ -0,0 +1,172 @@
+package ctxlog
+
+import (
+	"context"
+	"log/slog"
+)
+
+type ctxSampledKey struct{}
+
+var sampledKey = ctxSampledKey{} //nolint:gochecknoglobals // Required for context key
+
+// Sample makes a sampling decision once and pins it into the returned context.
+// Subsequent From calls with WithSampling or WithSampleKey inherit the pinned
+// decision instead of drawing a new one, so sampling is coherent across the whole
+// call tree of a request. If the context already has a decision, it is kept.
+//
+// Example:
+//
+//	ctx = ctxlog.Sample(ctx, 0.1)                     // at request entry
+//	logger := ctxlog.From(ctx, ctxlog.WithSampling(0.1)) // same decision everywhere
+func Sample(ctx context.Context, rate float64) context.Context {
+	if _, ok := sampledFrom(ctx); ok {
+		return ctx
+	}
+	return SetSampled(ctx, cryptoRandFloat64() <= rate)
+}
+
+// SetSampled overrides the sampling decision for the returned context and its
+// children, e.g. to force keeping logs once an error is observed.
+func SetSampled(ctx context.Context, sampled bool) context.Context {
+	return context.WithValue(ctx, sampledKey, sampled)
+}
+
+// sampledFrom returns the sampling decision pinned into the context, if any.
+func sampledFrom(ctx context.Context) (bool, bool) {
+	sampled, ok := ctx.Value(sampledKey).(bool)
+	return sampled, ok
+}
+
+// levelRate is a sampling rate that applies from level up to the next
+// configured level.
+type levelRate struct {
+	level slog.Level
+	rate  float64
+}
+
+// samplingHandler samples records by level, so that Warn and above pass unless
+// a rate is configured for them. The decision of WithSampling is made once per
+// logger, while rates set by WithLevelSampling are drawn per record.
+type samplingHandler struct {
+	base     slog.Handler
+	rate     *float64    // rate of levels below Warn set by WithSampling
+	rates    []levelRate // rates set by WithLevelSampling, sorted by level
+	pinned   *bool       // decision pinned by Sample or SetSampled
+	value    *float64    // hashed sample key compared with the rate
+	drawn    bool        // decision of WithSampling drawn for the logger
+	fastRand bool
+}
+
+// newSamplingHandler creates a sampling handler for the options of From.
+func newSamplingHandler(ctx context.Context, base slog.Handler, cfg *config) *samplingHandler {
+	h := &samplingHandler{
+		base:     base,
+		rate:     cfg.sampling,
+		rates:    cfg.levelRate,
+		fastRand: cfg.fastRand,
+	}
+	if sampled, ok := sampledFrom(ctx); ok {
+		h.pinned = &sampled
+	}
+	if cfg.sampleKey != nil {
+		value := hashFloat64(cfg.sampleKey(ctx))
+		h.value = &value
+	}
+	if h.rate != nil && h.pinned == nil && h.value == nil {
+		h.drawn = h.draw(*h.rate)
+	}
+	return h
+}
+
+// draw makes a random sampling decision against rate.
+func (h *samplingHandler) draw(rate float64) bool {
+	switch {
+	case rate <= 0:
+		return false
+	case rate >= 1:
+		return true
+	case h.fastRand:
+		return fastRandFloat64() <= rate
+	default:
+		return cryptoRandFloat64() <= rate
+	}
+}
+
+// levelRateFor returns the rate set by WithLevelSampling for level. A level
+// rate applies to higher levels up to the next configured one, except that it
+// never applies to Warn and above from a level below Warn.
+func (h *samplingHandler) levelRateFor(level slog.Level) (float64, bool) {
+	for i := len(h.rates) - 1; i >= 0; i-- {
+		r := h.rates[i]
+		if r.level > level {
+			continue
+		}
+		if level >= slog.LevelWarn && r.level < slog.LevelWarn {
+			break
+		}
+		return r.rate, true
+	}
+	return 0, false
+}
+
+// decide returns whether a record at level is kept. The second result is
+// false if the decision needs a random draw per record against the returned
+// rate.
+func (h *samplingHandler) decide(level slog.Level) (bool, bool, float64) {
+	rate, perRecord := h.levelRateFor(level)
+	if !perRecord {
+		if h.rate == nil || level >= slog.LevelWarn {
+			return true, true, 0
+		}
+		rate = *h.rate
+	}
+
+	switch {
+	case h.pinned != nil:
+		return *h.pinned, true, rate
+	case h.value != nil:
+		return *h.value <= rate, true, rate
+	case !perRecord:
+		return h.drawn, true, rate
+	case rate <= 0:
+		return false, true, rate
+	case rate >= 1:
+		return true, true, rate
+	default:
+		return false, false, rate
+	}
+}
+
+// Enabled reports false for levels whose records are dropped without a
+// per-record draw, so that sampled-out loggers cost nothing.
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if !h.base.Enabled(ctx, level) {
+		return false
+	}
+	keep, decided, _ := h.decide(level)
+	return keep || !decided
+}
+
+//nolint:gocritic // slog.Record must be passed by value per slog.Handler interface
+func (h *samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	keep, decided, rate := h.decide(record.Level)
+	if !decided {
+		keep = h.draw(rate)
+	}
+	if !keep {
+		return nil
+	}
+	return h.base.Handle(ctx, record)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.base = h.base.WithAttrs(attrs)
+	return &clone
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.base = h.base.WithGroup(name)
+	return &clone
+}
@@ -0,0 +1,193 @@
+package ctxlog_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/m-mizutani/ctxlog"
+)
+
+func TestSample(t *testing.T) {
+	ctx := t.Context()
+
+	// Pinned decision overrides per-call sampling
+	dropped := ctxlog.Sample(ctx, 0.0)
+	if ctxlog.From(dropped, ctxlog.WithSampling(1.0)).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Pinned drop decision should be inherited by WithSampling")
+	}
+	if ctxlog.From(dropped, ctxlog.WithSampleKey("req-1", 1.0)).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Pinned drop decision should be inherited by WithSampleKey")
+	}
+
+	kept := ctxlog.Sample(ctx, 1.0)
+	if !ctxlog.From(kept, ctxlog.WithSampling(0.0)).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Pinned keep decision should be inherited by WithSampling")
+	}
+
+	// Loggers without sampling options are not affected
+	if !ctxlog.From(dropped).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Pinned decision should only apply to sampled loggers")
+	}
+
+	// Sample keeps an existing decision
+	if ctxlog.From(ctxlog.Sample(dropped, 1.0), ctxlog.WithSampling(1.0)).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Sample should not re-roll an existing decision")
+	}
+}
+
+func TestSetSampled(t *testing.T) {
+	ctx := ctxlog.Sample(t.Context(), 0.0)
+
+	// Child context forces keeping logs
+	child := ctxlog.SetSampled(ctx, true)
+	if !ctxlog.From(child, ctxlog.WithSampling(0.5)).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Child context should be able to force keeping logs")
+	}
+
+	// Parent decision is unchanged
+	if ctxlog.From(ctx, ctxlog.WithSampling(0.5)).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Parent context decision should not be changed by child")
+	}
+}
+
+func TestLevelSampling(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	ctx := ctxlog.With(t.Context(), slog.New(handler))
+
+	logger := ctxlog.From(ctx, ctxlog.WithLevelSampling(map[slog.Level]float64{
+		slog.LevelDebug: 0.0,
+		slog.LevelInfo:  1.0,
+	}))
+
+	// Levels are decided per record, so only always-dropped levels are disabled
+	if logger.Enabled(ctx, slog.LevelDebug) || !logger.Enabled(ctx, slog.LevelInfo) {
+		t.Error("Level sampling logger should be disabled only for levels with rate 0")
+	}
+
+	logger.Debug("debug message")
+	logger.Info("info message")
+	logger.Warn("warn message")
+	logger.Error("error message")
+
+	out := buf.String()
+	if strings.Contains(out, "debug message") {
+		t.Error("Debug records should be dropped with rate 0")
+	}
+	for _, msg := range []string{"info message", "warn message", "error message"} {
+		if !strings.Contains(out, msg) {
+			t.Errorf("Expected %q to be logged", msg)
+		}
+	}
+}
+
+func TestLevelSamplingExplicitError(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(&buf, nil)))
+
+	logger := ctxlog.From(ctx, ctxlog.WithLevelSampling(map[slog.Level]float64{
+		slog.LevelError: 0.0,
+	}))
+	logger.Error("error message")
+	logger.Info("info message")
+
+	if strings.Contains(buf.String(), "error message") {
+		t.Error("Explicitly configured Error rate should be applied")
+	}
+	if !strings.Contains(buf.String(), "info message") {
+		t.Error("Unconfigured levels should always be logged")
+	}
+}
+
+func TestSamplingKeepsWarnAndAbove(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(&buf, nil)))
+
+	for _, opt := range []ctxlog.Option{ctxlog.WithSampling(0.0), ctxlog.WithSampleKey("req-1", 0.0)} {
+		logger := ctxlog.From(ctx, opt)
+		logger.Info("info message")
+		logger.Warn("warn message")
+		logger.Error("error message")
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "info message") {
+		t.Error("Info records should be sampled")
+	}
+	if strings.Count(out, "warn message") != 2 || strings.Count(out, "error message") != 2 {
+		t.Errorf("Warn and above should never be dropped by sampling: %s", out)
+	}
+
+	// A pinned drop decision does not drop errors either
+	buf.Reset()
+	ctxlog.From(ctxlog.SetSampled(ctx, false), ctxlog.WithSampling(1.0)).Error("pinned error")
+	if !strings.Contains(buf.String(), "pinned error") {
+		t.Error("Pinned decision should not drop errors")
+	}
+}
+
+func TestSamplingDecidedPerLogger(t *testing.T) {
+	ctx, capture := ctxlog.NewCapture(t.Context())
+
+	for range 20 {
+		capture.Reset()
+		logger := ctxlog.From(ctx, ctxlog.WithSampling(0.5))
+		enabled := logger.Enabled(ctx, slog.LevelInfo)
+		for range 10 {
+			logger.Info("sampled")
+		}
+		logger.Warn("warn")
+
+		// All records below Warn are kept or dropped together
+		if n := capture.Count(ctxlog.ByLevel(slog.LevelInfo)); (enabled && n != 10) || (!enabled && n != 0) {
+			t.Fatalf("Expected all or no records for one logger (enabled: %v), got %d", enabled, n)
+		}
+		if n := capture.Count(ctxlog.ByLevel(slog.LevelWarn)); n != 1 {
+			t.Fatalf("Expected warn record to pass, got %d", n)
+		}
+	}
+}
+
+func TestLevelSamplingPinnedDecision(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(&buf, nil)))
+	rates := ctxlog.WithLevelSampling(map[slog.Level]float64{slog.LevelInfo: 0.5})
+
+	for range 20 {
+		ctxlog.From(ctxlog.SetSampled(ctx, true), rates).Info("kept")
+		ctxlog.From(ctxlog.SetSampled(ctx, false), rates).Info("dropped")
+	}
+
+	if n := strings.Count(buf.String(), "kept"); n != 20 {
+		t.Errorf("Expected pinned keep decision to keep all records, got %d", n)
+	}
+	if strings.Contains(buf.String(), "dropped") {
+		t.Error("Expected pinned drop decision to drop sampled records")
+	}
+}
+
+func TestLevelSamplingCustomLevels(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	ctx := ctxlog.With(t.Context(), slog.New(handler))
+
+	logger := ctxlog.From(ctx, ctxlog.WithLevelSampling(map[slog.Level]float64{
+		slog.LevelInfo: 0.0,
+	}))
+	logger.Log(ctx, slog.LevelInfo+2, "notice message")
+	logger.Log(ctx, slog.LevelWarn+2, "severe message")
+	logger.Debug("debug message")
+
+	out := buf.String()
+	if strings.Contains(out, "notice message") {
+		t.Error("Info rate should apply to custom levels above Info")
+	}
+	if !strings.Contains(out, "severe message") {
+		t.Error("Info rate should not apply to levels at or above Warn")
+	}
+	if !strings.Contains(out, "debug message") {
+		t.Error("Levels below the lowest configured level should pass")
+	}
+}
@@ -2,8 +2,14 @@ package ctxlog
 
 import (
 	"context"
+	"log/slog"
 	"os"
+	"reflect"
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Scope system provides hierarchical and conditional logger activation.
@@ -37,6 +43,7 @@ import (
 //   // Scenario B: Multiple conditions
 //   os.Unsetenv("DEBUG_API")                             // Remove env var
 //   os.Setenv("VERBOSE_API", "")                         // Set different env var (empty value)
+//   ctxlog.RefreshScopes()                               // Re-read env vars (snapshotted on first use)
 //   logger = ctxlog.From(ctx, apiScope)                  // Active (VERBOSE_API exists)
 //   logger.Error("API error occurred")                   // This will be logged
 //
@@ -49,6 +56,7 @@ import (
 //   ctx = context.Background()                           // Reset context
 //   os.Unsetenv("DEBUG_API")                             // No env vars
 //   os.Unsetenv("VERBOSE_API")
+//   ctxlog.RefreshScopes()
 //   logger = ctxlog.From(ctx, apiScope)                  // Inactive (no env vars, not enabled)
 //   logger.Info("This won't be logged")                  // Returns discard logger
 //
@@ -57,17 +65,55 @@ import (
 //   logger = ctxlog.From(ctx, dbScope)                   // Active (globally enabled)
 //   logger.Info("Database query")                        // This will be logged
 //
-//   ctxlog.DisableScopeGlobal(dbScope)                   // Disable globally
-//   logger = ctxlog.From(ctx, dbScope)                   // Inactive (not globally enabled, no DEBUG_DB)
+//   ctxlog.DisableScopeGlobal(dbScope)                   // Undo global enablement
+//   logger = ctxlog.From(ctx, dbScope)                   // Inactive unless DEBUG_DB is set
+//
+//   ctxlog.DenyScopeGlobal(dbScope)                      // Deny globally
+//   logger = ctxlog.From(ctx, dbScope)                   // Inactive (globally denied, even if DEBUG_DB is set)
 //   logger.Info("This won't be logged")                  // Returns discard logger
+//
+//   // 6. Explicit deny in context
+//   ctx = ctxlog.EnableScope(ctx, apiScope)
+//   ctx = ctxlog.DisableScope(ctx, userApiScope)         // Silence a noisy child
+//   logger = ctxlog.From(ctx, userApiScope)              // Inactive (deny beats parent activation)
 
 // Scope represents a logging scope with hierarchical support
 type Scope struct {
+	registry *Registry
 	name     string
-	envVars  []string
+	envConds []envCondition
+	funcs    []func(ctx context.Context) bool
+	level    *slog.Level
+	handler  *slog.Logger // logger of the handler set by WithHandler
 	parent   *Scope
 	children []*Scope
 	mu       sync.RWMutex
+
+	// flags is the compiled activation bitset read lock-free by isActive
+	globalMu      sync.Mutex // serializes updates of global state with expiration
+	flags         atomic.Uint32
+	expires       atomic.Int64 // deadline of global enablement in Unix nanoseconds, 0 if none
+	globalLevel   atomic.Pointer[slog.Level]
+	globalHandler atomic.Pointer[slog.Logger] // logger of the handler set by SetScopeHandler
+	cached        atomic.Pointer[scopedLogger]
+}
+
+// Activation bits of Scope.flags
+const (
+	scopeFlagEnvLoaded uint32 = 1 << iota // env conditions have been evaluated
+	scopeFlagEnvActive                    // env conditions are satisfied
+	scopeFlagAllow                        // enabled globally
+	scopeFlagDeny                         // disabled globally
+)
+
+// scopedLogger caches the logger derived from a base logger and the context
+// attributes for a scope.
+type scopedLogger struct {
+	base     *slog.Logger
+	attrs    []slog.Attr
+	level    slog.Level
+	hasLevel bool
+	logger   *slog.Logger
 }
 
 // ScopeOption defines a functional option for Scope configuration
@@ -75,26 +121,112 @@ type ScopeOption func(*scopeConfig)
 
 // scopeConfig holds configuration for Scope creation
 type scopeConfig struct {
-	envVars []string
+	envConds []envCondition
+	funcs    []func(ctx context.Context) bool
+	level    *slog.Level
+	handler  slog.Handler
+}
+
+// envCondition activates a scope by an environment variable.
+type envCondition struct {
+	name   string
+	kind   envConditionKind
+	values []string
+}
+
+// envConditionKind defines how an environment variable is evaluated.
+type envConditionKind int
+
+const (
+	envExists envConditionKind = iota // variable is set, any value
+	envValue                          // variable is set to one of values
+	envTruthy                         // variable is true by strconv.ParseBool
+)
+
+// check reports whether the environment variable satisfies the condition.
+func (c *envCondition) check() bool {
+	value, exists := os.LookupEnv(c.name)
+	if !exists {
+		return false
+	}
+
+	switch c.kind {
+	case envValue:
+		return slices.Contains(c.values, value)
+	case envTruthy:
+		enabled, err := strconv.ParseBool(value)
+		return err == nil && enabled
+	default:
+		return true
+	}
+}
+
+// equal reports whether both configurations define the same scope.
+// Predicates set by EnabledByFunc are compared by count, since functions are
+// not comparable. Handlers set by WithHandler are compared by value when their
+// type is comparable and by type otherwise.
+func (c *scopeConfig) equal(other *scopeConfig) bool {
+	if len(c.funcs) != len(other.funcs) || !sameHandler(c.handler, other.handler) {
+		return false
+	}
+	if (c.level == nil) != (other.level == nil) || (c.level != nil && *c.level != *other.level) {
+		return false
+	}
+	return slices.EqualFunc(c.envConds, other.envConds, func(a, b envCondition) bool {
+		return a.name == b.name && a.kind == b.kind && slices.Equal(a.values, b.values)
+	})
+}
+
+// sameHandler reports whether a and b are the same handler. Handlers that are
+// not comparable, including structs holding slices or maps in interface
+// fields, are considered the same if their types match.
+func sameHandler(a, b slog.Handler) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() {
+		return false
+	}
+	if !va.Comparable() || !vb.Comparable() {
+		return true
+	}
+	return a == b
 }
 
 var (
-	globalScopes  = make(map[string]*Scope) //nolint:gochecknoglobals // Required for scope registry
-	scopesMu      sync.RWMutex              //nolint:gochecknoglobals // Required for scope registry
-	enabledScopes = make(map[string]*Scope) //nolint:gochecknoglobals // Required for scope management
-	enabledMu     sync.RWMutex              //nolint:gochecknoglobals // Required for scope management
+	expiredHooks   []*expiredHook //nolint:gochecknoglobals // Required for scope expiration events
+	expiredHooksMu sync.RWMutex   //nolint:gochecknoglobals // Required for scope expiration events
 )
 
+// expiredHook is a function registered by OnScopeExpired. It is referenced by
+// pointer so that it can be removed.
+type expiredHook struct {
+	fn func(scope *Scope)
+}
+
+// now returns the current time for scope deadlines. It is replaced in tests.
+var now = time.Now //nolint:gochecknoglobals // Required for replacing the clock in tests
+
+// scopeAttrKey is the attribute key of the scope name added to scoped loggers.
+const scopeAttrKey = "ctxlog.scope"
+
 type ctxEnabledScopesKey struct{}
 
 var enabledScopesKey = ctxEnabledScopesKey{} //nolint:gochecknoglobals // Required for context key
 
+type ctxScopeLevelsKey struct{}
+
+var scopeLevelsKey = ctxScopeLevelsKey{} //nolint:gochecknoglobals // Required for context key
+
 // EnabledBy creates a ScopeOption that enables scope activation via environment variables.
 //
 // Multiple environment variables behavior:
 //   - If ANY of the specified environment variables is set (even to empty string),
 //     the scope will be activated.
 //   - Environment variables are checked with os.LookupEnv(), so existence matters, not value.
+//   - Environment variables are read on first use of the scope and cached.
+//     Call RefreshScopes (or use WatchScopes) after changing them at runtime.
 //
 // Example:
 //
@@ -106,7 +238,84 @@ var enabledScopesKey = ctxEnabledScopesKey{} //nolint:gochecknoglobals // Requir
 //	unset DEBUG_API VERBOSE_API  # scope is inactive
 func EnabledBy(envVars ...string) ScopeOption {
 	return func(cfg *scopeConfig) {
-		cfg.envVars = envVars
+		for _, envVar := range envVars {
+			cfg.envConds = append(cfg.envConds, envCondition{name: envVar})
+		}
+	}
+}
+
+// EnabledByValue creates a ScopeOption that enables scope activation when the
+// environment variable is set to one of the given values.
+//
+// Example:
+//
+//	scope := ctxlog.NewScope("api", ctxlog.EnabledByValue("DEBUG_API", "1", "true"))
+//
+//	export DEBUG_API=1      # scope is active
+//	export DEBUG_API=false  # scope is inactive
+func EnabledByValue(envVar string, values ...string) ScopeOption {
+	return func(cfg *scopeConfig) {
+		cfg.envConds = append(cfg.envConds, envCondition{
+			name:   envVar,
+			kind:   envValue,
+			values: values,
+		})
+	}
+}
+
+// EnabledByTruthy creates a ScopeOption that enables scope activation when the
+// environment variable is set to a true value by strconv.ParseBool semantics
+// ("1", "t", "T", "TRUE", "true", "True"). Values such as "0", "false" or an
+// empty string do not activate the scope.
+func EnabledByTruthy(envVar string) ScopeOption {
+	return func(cfg *scopeConfig) {
+		cfg.envConds = append(cfg.envConds, envCondition{
+			name: envVar,
+			kind: envTruthy,
+		})
+	}
+}
+
+// EnabledByFunc creates a ScopeOption that enables scope activation when the
+// predicate returns true for the context passed to From.
+//
+// Example:
+//
+//	scope := ctxlog.NewScope("admin", ctxlog.EnabledByFunc(func(ctx context.Context) bool {
+//	    return isAdminRequest(ctx)
+//	}))
+func EnabledByFunc(fn func(ctx context.Context) bool) ScopeOption {
+	return func(cfg *scopeConfig) {
+		cfg.funcs = append(cfg.funcs, fn)
+	}
+}
+
+// WithLevel creates a ScopeOption that sets the minimum log level of the scope.
+// When the scope is active, the logger returned by From accepts records at or
+// above this level regardless of the base handler's level, so debug output can
+// be enabled for one scope while the rest of the application stays at Info.
+// Child scopes inherit the level unless they set their own.
+//
+// Example:
+//
+//	dbScope := ctxlog.NewScope("database", ctxlog.EnabledBy("DEBUG_DB"), ctxlog.WithLevel(slog.LevelDebug))
+func WithLevel(level slog.Level) ScopeOption {
+	return func(cfg *scopeConfig) {
+		cfg.level = &level
+	}
+}
+
+// WithHandler creates a ScopeOption that sends the scope's logs to handler
+// instead of the logger embedded in the context, e.g. to write debug logs of
+// a scope to a separate file. Child scopes inherit the handler unless they
+// set their own. Attributes added by WithAttrs are still applied.
+//
+// Since the context logger is replaced, the scope's logs bypass loggers set
+// up by NewBuffer, NewCapture and Capture.Tap. Add handler to the context
+// logger with Multi and FilterHandler instead to keep them.
+func WithHandler(handler slog.Handler) ScopeOption {
+	return func(cfg *scopeConfig) {
+		cfg.handler = handler
 	}
 }
 
@@ -117,7 +326,11 @@ func EnabledBy(envVars ...string) ScopeOption {
 // Available activation conditions:
 // 1. Dynamic enablement via EnableScope(ctx, scope) or EnableScopeGlobal(scope)
 // 2. Parent scope activation (children inherit parent's active state)
-// 3. Environment variable existence (via EnabledBy option)
+// 3. Environment variable existence or value (via EnabledBy, EnabledByValue, EnabledByTruthy options)
+// 4. Context-aware predicate (via EnabledByFunc option)
+//
+// An explicit deny via DisableScope(ctx, scope) or DenyScopeGlobal(scope)
+// takes precedence over parent activation and environment variables.
 //
 // Combined options examples:
 //
@@ -135,134 +348,530 @@ func EnabledBy(envVars ...string) ScopeOption {
 //
 //	scope := ctxlog.NewScope("manual")
 //	// Only active via EnableScope(ctx, scope) or EnableScopeGlobal(scope)
+//
+// NewScope registers the scope in the default registry. If a scope with the
+// same name already exists, it is returned as is; in strict mode (see
+// (*Registry).SetStrict) NewScope panics if the options differ. Use Register
+// to get an error instead.
 func NewScope(name string, options ...ScopeOption) *Scope {
-	scopesMu.Lock()
-	defer scopesMu.Unlock()
+	return defaultRegistry.NewScope(name, options...)
+}
 
-	if existing, exists := globalScopes[name]; exists {
-		return existing
-	}
+// Register registers a scope in the default registry like NewScope, but
+// returns an error wrapping ErrScopeConflict if a scope with the same name
+// was registered with different options.
+func Register(name string, options ...ScopeOption) (*Scope, error) {
+	return defaultRegistry.Register(name, options...)
+}
 
-	cfg := &scopeConfig{}
-	for _, opt := range options {
-		opt(cfg)
+// NewChild creates a child scope with hierarchical naming in the registry of
+// the parent. Calling NewChild again with the same name returns the existing
+// child, following the same conflict rules as NewScope.
+func (s *Scope) NewChild(name string, options ...ScopeOption) *Scope {
+	child, err := s.registry.register(s.name+"."+name, s, options)
+	if err != nil && s.registry.isStrict() {
+		panic(err)
 	}
+	return child
+}
+
+// isActive checks if the scope is active based on context, environment variables or dynamic enablement.
+//
+// Activation priority (checked in this order, first match wins):
+// 1. Context-based deny (DisableScope)
+// 2. Context-based enablement (EnableScope)
+// 3. Global deny (DenyScopeGlobal)
+// 4. Global dynamic enablement (EnableScopeGlobal)
+// 5. Parent scope state (recursive check; a denied parent denies its children)
+// 6. Environment variable conditions (EnabledBy, EnabledByValue, EnabledByTruthy options)
+// 7. Context-aware predicates (EnabledByFunc option)
+func (s *Scope) isActive(ctx context.Context) bool {
+	contextScopes, _ := ctx.Value(enabledScopesKey).(map[string]contextScope)
+	return s.evaluate(ctx, contextScopes).Active
+}
+
+// ScopeRule identifies the activation rule that decided a scope's state.
+type ScopeRule string
+
+// Activation rules reported by (*Scope).Explain
+const (
+	ScopeRuleNone         ScopeRule = "none"          // no rule matched, scope is inactive
+	ScopeRuleContextDeny  ScopeRule = "context-deny"  // DisableScope
+	ScopeRuleContextAllow ScopeRule = "context-allow" // EnableScope, EnableScopeFor
+	ScopeRuleGlobalDeny   ScopeRule = "global-deny"   // DenyScopeGlobal
+	ScopeRuleGlobalAllow  ScopeRule = "global-allow"  // EnableScopeGlobal, EnableScopeGlobalFor
+	ScopeRuleEnv          ScopeRule = "env"           // EnabledBy, EnabledByValue, EnabledByTruthy
+	ScopeRuleFunc         ScopeRule = "func"          // EnabledByFunc
+)
+
+// Activation explains why a scope is active or inactive.
+type Activation struct {
+	// Active reports whether the scope is active.
+	Active bool
+	// Rule is the rule that decided the state.
+	Rule ScopeRule
+	// Scope is the scope whose rule matched: the scope itself, or an ancestor
+	// if the state is inherited. Nil if no rule matched.
+	Scope *Scope
+}
 
-	scope := &Scope{
-		name:    name,
-		envVars: cfg.envVars,
+// String returns a human readable explanation, e.g. "active by global-allow of api".
+func (a Activation) String() string {
+	if a.Scope == nil {
+		return "inactive"
 	}
 
-	globalScopes[name] = scope
-	return scope
+	state := "inactive"
+	if a.Active {
+		state = "active"
+	}
+	return state + " by " + string(a.Rule) + " of " + a.Scope.name
 }
 
-// NewChild creates a child scope with hierarchical naming
-func (s *Scope) NewChild(name string, options ...ScopeOption) *Scope {
-	fullName := s.name + "." + name
-	child := NewScope(fullName, options...)
-	child.parent = s
+// evaluate evaluates the activation rules of the scope. See isActive for the order.
+// Global rules and environment variables are read from the compiled bitset,
+// so no lock is taken.
+func (s *Scope) evaluate(ctx context.Context, contextScopes map[string]contextScope) Activation {
+	// Check context-based deny and enablement first, ignoring expired enablement
+	if stored, exists := contextScopes[s.name]; exists {
+		if rule := stored.current(); rule != nil {
+			if !rule.enabled {
+				return Activation{Active: false, Rule: ScopeRuleContextDeny, Scope: s}
+			}
+			return Activation{Active: true, Rule: ScopeRuleContextAllow, Scope: s}
+		}
+	}
+
+	// Check global deny and dynamic enablement
+	flags := s.flags.Load()
+	if flags&scopeFlagDeny != 0 {
+		return Activation{Active: false, Rule: ScopeRuleGlobalDeny, Scope: s}
+	}
+	if flags&scopeFlagAllow != 0 && !s.expireGlobal() {
+		return Activation{Active: true, Rule: ScopeRuleGlobalAllow, Scope: s}
+	}
 
-	s.mu.Lock()
-	s.children = append(s.children, child)
-	s.mu.Unlock()
+	// Check parent state (parent activation enables all children, parent deny disables them)
+	if s.parent != nil {
+		if inherited := s.parent.evaluate(ctx, contextScopes); inherited.Scope != nil {
+			return inherited
+		}
+	}
 
-	return child
+	// Check environment variables snapshot, evaluated on first use
+	if flags&scopeFlagEnvLoaded == 0 {
+		flags = s.loadEnv()
+	}
+	if flags&scopeFlagEnvActive != 0 {
+		return Activation{Active: true, Rule: ScopeRuleEnv, Scope: s}
+	}
+
+	// Check predicates
+	for _, fn := range s.funcs {
+		if fn(ctx) {
+			return Activation{Active: true, Rule: ScopeRuleFunc, Scope: s}
+		}
+	}
+
+	return Activation{Rule: ScopeRuleNone}
 }
 
-// isActive checks if the scope is active based on context, environment variables or dynamic enablement.
+// Active reports whether the scope is active in the context, i.e. whether
+// From(ctx, scope) returns a logger that writes output.
+func (s *Scope) Active(ctx context.Context) bool {
+	return resolveScope(ctx, s).isActive(ctx)
+}
+
+// Explain reports whether the scope is active in the context and which rule
+// decided it.
 //
-// Activation priority (checked in this order):
-// 1. Context-based dynamic enablement (EnableScope)
-// 2. Global dynamic enablement (EnableScopeGlobal)
-// 3. Parent scope activation (recursive check)
-// 4. Environment variable existence (EnabledBy option)
+// Example:
 //
-// Returns true if ANY condition is met (OR logic).
-func (s *Scope) isActive(ctx context.Context) bool {
-	// Check context-based enablement first
-	if enabledScopes, ok := ctx.Value(enabledScopesKey).(map[string]bool); ok {
-		if enabled, exists := enabledScopes[s.name]; exists && enabled {
-			return true
-		}
+//	fmt.Println(userScope.Explain(ctx)) // "active by context-allow of api"
+func (s *Scope) Explain(ctx context.Context) Activation {
+	contextScopes, _ := ctx.Value(enabledScopesKey).(map[string]contextScope)
+	return resolveScope(ctx, s).evaluate(ctx, contextScopes)
+}
+
+// expireGlobal disables the global enablement of the scope if its deadline has
+// passed, and reports whether it is expired. Expiration is enforced lazily when
+// the scope is checked, so no goroutine is needed per scope.
+func (s *Scope) expireGlobal() bool {
+	expires := s.expires.Load()
+	if expires == 0 || now().UnixNano() < expires {
+		return false
 	}
 
-	// Check global dynamic enablement
-	enabledMu.RLock()
-	if _, exists := enabledScopes[s.name]; exists {
-		enabledMu.RUnlock()
-		return true
+	// Re-check under the lock, since the scope may have been enabled again
+	// after the deadline was loaded. Only one caller wins and notifies.
+	s.globalMu.Lock()
+	expired := s.expires.Load() == expires
+	if expired {
+		s.expires.Store(0)
+		s.updateFlags(0, scopeFlagAllow)
 	}
-	enabledMu.RUnlock()
+	s.globalMu.Unlock()
 
-	// Check parent activation (parent activation enables all children)
-	if s.parent != nil && s.parent.isActive(ctx) {
+	if expired {
+		notifyScopeExpired(s)
 		return true
 	}
+	return s.flags.Load()&scopeFlagAllow == 0
+}
+
+// setGlobal sets the global deadline and activation bits of the scope.
+func (s *Scope) setGlobal(expires int64, set, clear uint32) {
+	s.globalMu.Lock()
+	defer s.globalMu.Unlock()
+
+	s.expires.Store(expires)
+	s.updateFlags(set, clear)
+}
+
+// loadEnv evaluates environment variable conditions and stores the result in
+// the activation bitset. It returns the updated flags.
+func (s *Scope) loadEnv() uint32 {
+	active := false
+	for i := range s.envConds {
+		if s.envConds[i].check() {
+			active = true
+			break
+		}
+	}
+
+	if active {
+		return s.updateFlags(scopeFlagEnvLoaded|scopeFlagEnvActive, 0)
+	}
+	return s.updateFlags(scopeFlagEnvLoaded, scopeFlagEnvActive)
+}
 
-	// Check environment variables
-	for _, envVar := range s.envVars {
-		if _, exists := os.LookupEnv(envVar); exists {
-			return true
+// updateFlags atomically sets and clears bits of the activation bitset and
+// returns the updated flags.
+func (s *Scope) updateFlags(set, clear uint32) uint32 {
+	for {
+		old := s.flags.Load()
+		updated := (old &^ clear) | set
+		if s.flags.CompareAndSwap(old, updated) {
+			return updated
 		}
 	}
+}
 
-	return false
+// RefreshScopes re-evaluates environment variable conditions of all registered
+// scopes. Scopes snapshot their environment variables on first use, so call this
+// after changing environment variables at runtime, or use WatchScopes.
+func RefreshScopes() {
+	defaultRegistry.Refresh()
+}
+
+// WatchScopes calls RefreshScopes at the given interval until ctx is canceled.
+func WatchScopes(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				RefreshScopes()
+			}
+		}
+	}()
 }
 
 // EnableScope returns a new context with the given scopes enabled
 func EnableScope(ctx context.Context, scopes ...*Scope) context.Context {
-	var contextScopes map[string]bool
+	return setContextScopes(ctx, contextScope{enabled: true}, scopes)
+}
 
-	// Get existing enabled scopes from context
-	if existing, ok := ctx.Value(enabledScopesKey).(map[string]bool); ok {
-		// Copy existing map
-		contextScopes = make(map[string]bool)
+// EnableScopeFor returns a new context with the given scopes enabled until the
+// duration elapses. After the deadline, activation falls back to the rule the
+// scope had in ctx, if any, and then to global state, parent scopes and
+// environment variables.
+func EnableScopeFor(ctx context.Context, d time.Duration, scopes ...*Scope) context.Context {
+	return setContextScopes(ctx, contextScope{enabled: true, expires: now().Add(d).UnixNano()}, scopes)
+}
+
+// DisableScope returns a new context with the given scopes explicitly disabled.
+// The deny takes precedence over global enablement, parent activation and
+// environment variables, and also disables the children of the given scopes.
+func DisableScope(ctx context.Context, scopes ...*Scope) context.Context {
+	return setContextScopes(ctx, contextScope{enabled: false}, scopes)
+}
+
+// contextScope is the activation rule of a scope stored in a context.
+type contextScope struct {
+	enabled  bool
+	expires  int64         // deadline in Unix nanoseconds, 0 if none
+	shadowed *contextScope // rule replaced by a rule with a deadline, in effect after it
+}
+
+// current returns the rule in effect, falling back to shadowed rules when the
+// deadline has passed, or nil if all rules have expired.
+func (r *contextScope) current() *contextScope {
+	var current int64
+	for rule := r; rule != nil; rule = rule.shadowed {
+		if rule.expires == 0 {
+			return rule
+		}
+		if current == 0 {
+			current = now().UnixNano()
+		}
+		if current < rule.expires {
+			return rule
+		}
+	}
+	return nil
+}
+
+// setContextScopes returns a new context with the activation of the given scopes set.
+func setContextScopes(ctx context.Context, rule contextScope, scopes []*Scope) context.Context {
+	contextScopes := make(map[string]contextScope)
+
+	// Copy existing scopes from context
+	if existing, ok := ctx.Value(enabledScopesKey).(map[string]contextScope); ok {
 		for k, v := range existing {
 			contextScopes[k] = v
 		}
-	} else {
-		contextScopes = make(map[string]bool)
 	}
 
-	// Add new scopes
 	for _, scope := range scopes {
-		contextScopes[scope.name] = true
+		scopeRule := rule
+		// Keep the replaced rule to fall back to after the deadline
+		if existing, ok := contextScopes[scope.name]; ok && rule.expires != 0 {
+			scopeRule.shadowed = existing.current()
+		}
+		contextScopes[scope.name] = scopeRule
 	}
 
 	return context.WithValue(ctx, enabledScopesKey, contextScopes)
 }
 
-// EnableScopeGlobal dynamically enables the given scopes globally
+// EnableScopeGlobal dynamically enables the given scopes globally, in the
+// registry each scope belongs to
 func EnableScopeGlobal(scopes ...*Scope) {
-	enabledMu.Lock()
-	defer enabledMu.Unlock()
+	for _, scope := range scopes {
+		scope.setGlobal(0, scopeFlagAllow, scopeFlagDeny)
+	}
+}
 
+// EnableScopeGlobalFor enables the given scopes globally until the duration
+// elapses. Expiration is checked lazily when the scope is used, and functions
+// registered by OnScopeExpired are called once the expiration is noticed.
+func EnableScopeGlobalFor(d time.Duration, scopes ...*Scope) {
+	expires := now().Add(d).UnixNano()
 	for _, scope := range scopes {
-		enabledScopes[scope.name] = scope
+		scope.setGlobal(expires, scopeFlagAllow, scopeFlagDeny)
 	}
 }
 
-// DisableScopeGlobal disables the given scopes globally
+// DisableScopeGlobal removes global enablement of the given scopes by
+// EnableScopeGlobal or EnableScopeGlobalFor, so their activation falls back to
+// parent scopes and environment variables. Use DenyScopeGlobal to silence a
+// scope regardless of them.
 func DisableScopeGlobal(scopes ...*Scope) {
-	enabledMu.Lock()
-	defer enabledMu.Unlock()
+	for _, scope := range scopes {
+		scope.setGlobal(0, 0, scopeFlagAllow)
+	}
+}
+
+// DenyScopeGlobal denies the given scopes globally.
+// The deny takes precedence over parent activation and environment variables,
+// but not over EnableScope in a context. Use ResetScopeGlobal to remove it.
+func DenyScopeGlobal(scopes ...*Scope) {
+	for _, scope := range scopes {
+		scope.setGlobal(0, scopeFlagDeny, scopeFlagAllow)
+	}
+}
 
+// ResetScopeGlobal removes global enablement and deny of the given scopes,
+// so their activation falls back to parent scopes and environment variables.
+func ResetScopeGlobal(scopes ...*Scope) {
 	for _, scope := range scopes {
-		delete(enabledScopes, scope.name)
+		scope.setGlobal(0, 0, scopeFlagAllow|scopeFlagDeny)
 	}
 }
 
-// GetGlobalEnabledScopes returns the globally enabled scopes
+// GetGlobalEnabledScopes returns the globally enabled scopes.
+// Use (*Scope).GlobalTTL to get the remaining time of scopes enabled by
+// EnableScopeGlobalFor.
 func GetGlobalEnabledScopes() []*Scope {
-	enabledMu.RLock()
-	defer enabledMu.RUnlock()
+	return defaultRegistry.GlobalEnabledScopes()
+}
 
-	scopes := make([]*Scope, 0, len(enabledScopes))
-	for _, scope := range enabledScopes {
-		scopes = append(scopes, scope)
+// GlobalTTL returns the remaining time until the global enablement of the scope
+// expires. It returns false if the scope is not globally enabled with a TTL.
+func (s *Scope) GlobalTTL() (time.Duration, bool) {
+	if s.flags.Load()&scopeFlagAllow == 0 || s.expireGlobal() {
+		return 0, false
 	}
-	return scopes
+
+	expires := s.expires.Load()
+	if expires == 0 {
+		return 0, false
+	}
+	return time.Unix(0, expires).Sub(now()), true
+}
+
+// OnScopeExpired registers a function called when the global enablement of a
+// scope set by EnableScopeGlobalFor expires. The function is called
+// synchronously by the goroutine that notices the expiration, so it should
+// return quickly. The returned function unregisters fn.
+func OnScopeExpired(fn func(scope *Scope)) func() {
+	hook := &expiredHook{fn: fn}
+
+	expiredHooksMu.Lock()
+	defer expiredHooksMu.Unlock()
+
+	expiredHooks = append(expiredHooks, hook)
+
+	return func() {
+		expiredHooksMu.Lock()
+		defer expiredHooksMu.Unlock()
+
+		// Copy so that hooks being notified are not modified
+		expiredHooks = slices.DeleteFunc(slices.Clone(expiredHooks), func(h *expiredHook) bool {
+			return h == hook
+		})
+	}
+}
+
+// notifyScopeExpired calls functions registered by OnScopeExpired.
+func notifyScopeExpired(scope *Scope) {
+	expiredHooksMu.RLock()
+	hooks := expiredHooks
+	expiredHooksMu.RUnlock()
+
+	for _, hook := range hooks {
+		hook.fn(scope)
+	}
+}
+
+// SetScopeLevel sets the minimum log level of the given scope globally.
+// It takes precedence over the level set by WithLevel.
+func SetScopeLevel(scope *Scope, level slog.Level) {
+	scope.globalLevel.Store(&level)
+}
+
+// ResetScopeLevel removes the global level set by SetScopeLevel.
+func ResetScopeLevel(scope *Scope) {
+	scope.globalLevel.Store(nil)
+}
+
+// WithScopeLevel returns a new context with the minimum log level of the given
+// scope set. It takes precedence over SetScopeLevel and WithLevel.
+func WithScopeLevel(ctx context.Context, scope *Scope, level slog.Level) context.Context {
+	contextLevels := make(map[string]slog.Level)
+	if existing, ok := ctx.Value(scopeLevelsKey).(map[string]slog.Level); ok {
+		for k, v := range existing {
+			contextLevels[k] = v
+		}
+	}
+	contextLevels[scope.name] = level
+
+	return context.WithValue(ctx, scopeLevelsKey, contextLevels)
+}
+
+// SetScopeHandler sends the logs of the given scope and its children without
+// their own handler to handler at runtime. It takes precedence over the
+// handler set by WithHandler and, like it, bypasses the context logger.
+func SetScopeHandler(scope *Scope, handler slog.Handler) {
+	scope.globalHandler.Store(slog.New(handler))
+}
+
+// ResetScopeHandler removes the handler set by SetScopeHandler.
+func ResetScopeHandler(scope *Scope) {
+	scope.globalHandler.Store(nil)
+}
+
+// handlerLogger returns the logger of the handler the scope's logs are sent
+// to, or nil to use the logger embedded in the context.
+//
+// Handler priority (checked in this order):
+// 1. Global handler (SetScopeHandler)
+// 2. Scope option (WithHandler)
+// 3. Parent scope handler (recursive check)
+func (s *Scope) handlerLogger() *slog.Logger {
+	if logger := s.globalHandler.Load(); logger != nil {
+		return logger
+	}
+
+	if s.handler != nil {
+		return s.handler
+	}
+
+	if s.parent != nil {
+		return s.parent.handlerLogger()
+	}
+
+	return nil
+}
+
+// effectiveLevel returns the minimum log level of the scope if one is configured.
+//
+// Level priority (checked in this order):
+// 1. Context-based level (WithScopeLevel)
+// 2. Global level (SetScopeLevel)
+// 3. Scope option (WithLevel)
+// 4. Parent scope level (recursive check)
+func (s *Scope) effectiveLevel(ctx context.Context) (slog.Level, bool) {
+	contextLevels, _ := ctx.Value(scopeLevelsKey).(map[string]slog.Level)
+	return s.resolveLevel(contextLevels)
+}
+
+// resolveLevel walks up the hierarchy to find the configured level.
+func (s *Scope) resolveLevel(contextLevels map[string]slog.Level) (slog.Level, bool) {
+	if level, exists := contextLevels[s.name]; exists {
+		return level, true
+	}
+
+	if level := s.globalLevel.Load(); level != nil {
+		return *level, true
+	}
+
+	if s.level != nil {
+		return *s.level, true
+	}
+
+	if s.parent != nil {
+		return s.parent.resolveLevel(contextLevels)
+	}
+
+	return 0, false
+}
+
+// logger returns the logger for the scope derived from base, with the context
+// attributes and the scope field added and the level overridden if configured.
+// The result is cached so that repeated calls with the same base logger and
+// attributes stored by WithAttrs, e.g. within one request, do not allocate.
+// The cache holds one entry per scope, so calls alternating between contexts
+// with different attributes allocate.
+func (s *Scope) logger(base *slog.Logger, attrs []slog.Attr, level slog.Level, hasLevel bool) *slog.Logger {
+	if cached := s.cached.Load(); cached != nil &&
+		cached.base == base && sameAttrs(cached.attrs, attrs) &&
+		cached.level == level && cached.hasLevel == hasLevel {
+		return cached.logger
+	}
+
+	handler := base.Handler()
+	if len(attrs) > 0 {
+		handler = handler.WithAttrs(attrs)
+	}
+	if hasLevel {
+		handler = &levelHandler{base: handler, level: level}
+	}
+	logger := slog.New(handler).With(scopeAttrKey, s.name)
+
+	s.cached.Store(&scopedLogger{base: base, attrs: attrs, level: level, hasLevel: hasLevel, logger: logger})
+	return logger
+}
+
+// sameAttrs reports whether a and b are the same slice. Slices stored by
+// WithAttrs are never modified, so identity implies equal attributes.
+func sameAttrs(a, b []slog.Attr) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
 }
 
 // Name returns the name of the scope
@@ -270,6 +879,40 @@ func (s *Scope) Name() string {
 	return s.name
 }
 
+// Parent returns the parent scope, or nil for a root scope.
+func (s *Scope) Parent() *Scope {
+	return s.parent
+}
+
+// Children returns the child scopes created by NewChild.
+func (s *Scope) Children() []*Scope {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	children := make([]*Scope, len(s.children))
+	copy(children, s.children)
+	return children
+}
+
+// EnvVars returns the environment variables that activate the scope.
+func (s *Scope) EnvVars() []string {
+	envVars := make([]string, 0, len(s.envConds))
+	for i := range s.envConds {
+		envVars = append(envVars, s.envConds[i].name)
+	}
+	return envVars
+}
+
+// Scopes returns all scopes registered in the default registry sorted by name.
+func Scopes() []*Scope {
+	return defaultRegistry.Scopes()
+}
+
+// LookupScope returns the scope with the given name in the default registry.
+func LookupScope(name string) (*Scope, bool) {
+	return defaultRegistry.Lookup(name)
+}
+
 // apply implements the Option interface
 func (s *Scope) apply(c *config) {
 	c.scope = s
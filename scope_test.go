@@ -1,8 +1,15 @@
 package ctxlog_test
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/m-mizutani/ctxlog"
 )
@@ -51,7 +58,7 @@ func TestScopeEnvironmentActivation(t *testing.T) {
 	ctx := t.Context()
 
 	// Set environment variable
-	t.Setenv("TEST_ENV_ACTIVATION", "1")
+	setScopeEnv(t, "TEST_ENV_ACTIVATION", "1")
 
 	logger := ctxlog.From(ctx, scope)
 	if !logger.Enabled(ctx, slog.LevelInfo) {
@@ -64,7 +71,7 @@ func TestScopeEnvironmentActivationWithEmptyValue(t *testing.T) {
 	ctx := t.Context()
 
 	// Set environment variable to empty string
-	t.Setenv("TEST_ENV_EMPTY", "")
+	setScopeEnv(t, "TEST_ENV_EMPTY", "")
 
 	logger := ctxlog.From(ctx, scope)
 	if !logger.Enabled(ctx, slog.LevelInfo) {
@@ -124,7 +131,7 @@ func TestChildScopeEnvironmentActivation(t *testing.T) {
 	ctx := t.Context()
 
 	// Test child activation through environment variable
-	t.Setenv("ENV_CHILD", "1")
+	setScopeEnv(t, "ENV_CHILD", "1")
 
 	logger := ctxlog.From(ctx, childScope)
 	if !logger.Enabled(ctx, slog.LevelInfo) {
@@ -146,7 +153,7 @@ func TestChildScopeParentEnvironmentActivation(t *testing.T) {
 	ctx := t.Context()
 
 	// Test child activation through parent environment variable
-	t.Setenv("ENV_PARENT2", "1")
+	setScopeEnv(t, "ENV_PARENT2", "1")
 
 	// Child should be active because parent is active
 	logger := ctxlog.From(ctx, childScope)
@@ -284,3 +291,514 @@ func TestGlobalScopeOperations(t *testing.T) {
 	// Clean up
 	ctxlog.DisableScopeGlobal(scope2)
 }
+
+func TestScopeLevel(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := ctxlog.With(t.Context(), slog.New(slog.NewTextHandler(&buf, nil)))
+
+	dbScope := ctxlog.NewScope("level-db", ctxlog.WithLevel(slog.LevelDebug))
+	queryScope := dbScope.NewChild("query")
+	ctx = ctxlog.EnableScope(ctx, dbScope)
+
+	ctxlog.From(ctx, dbScope).Debug("db debug")
+	ctxlog.From(ctx, queryScope).Debug("query debug")
+	ctxlog.From(ctx).Debug("app debug")
+
+	out := buf.String()
+	if !strings.Contains(out, "db debug") {
+		t.Error("Scope level should enable debug output for the scope")
+	}
+	if !strings.Contains(out, "query debug") {
+		t.Error("Child scope should inherit parent level")
+	}
+	if strings.Contains(out, "app debug") {
+		t.Error("Base logger level should be unchanged outside the scope")
+	}
+}
+
+func TestSetScopeLevel(t *testing.T) {
+	scope := ctxlog.NewScope("level-runtime")
+	ctx := ctxlog.EnableScope(t.Context(), scope)
+
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelDebug) {
+		t.Error("Scope without level should follow base handler level")
+	}
+
+	ctxlog.SetScopeLevel(scope, slog.LevelDebug)
+	t.Cleanup(func() { ctxlog.ResetScopeLevel(scope) })
+
+	if !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelDebug) {
+		t.Error("Global scope level should enable debug")
+	}
+
+	// Context level takes precedence over global level
+	warnCtx := ctxlog.WithScopeLevel(ctx, scope, slog.LevelWarn)
+	if ctxlog.From(warnCtx, scope).Enabled(warnCtx, slog.LevelInfo) {
+		t.Error("Context scope level should take precedence over global level")
+	}
+	if !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelDebug) {
+		t.Error("Parent context should not be affected by WithScopeLevel")
+	}
+
+	ctxlog.ResetScopeLevel(scope)
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelDebug) {
+		t.Error("Scope level should be removed after reset")
+	}
+}
+
+func TestDisableScope(t *testing.T) {
+	parentScope := ctxlog.NewScope("deny-parent")
+	childScope := parentScope.NewChild("noisy", ctxlog.EnabledBy("DENY_NOISY"))
+	setScopeEnv(t, "DENY_NOISY", "1")
+
+	ctx := ctxlog.EnableScope(t.Context(), parentScope)
+	ctx = ctxlog.DisableScope(ctx, childScope)
+
+	if !ctxlog.From(ctx, parentScope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Parent scope should remain active")
+	}
+	if ctxlog.From(ctx, childScope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Context deny should take precedence over parent activation and env var")
+	}
+
+	// Child context can re-enable
+	enabledCtx := ctxlog.EnableScope(ctx, childScope)
+	if !ctxlog.From(enabledCtx, childScope).Enabled(enabledCtx, slog.LevelInfo) {
+		t.Error("Child context should be able to re-enable a denied scope")
+	}
+
+	// Deny on parent propagates to children
+	deniedCtx := ctxlog.DisableScope(t.Context(), parentScope)
+	if ctxlog.From(deniedCtx, childScope).Enabled(deniedCtx, slog.LevelInfo) {
+		t.Error("Denied parent should deny children")
+	}
+}
+
+func TestDisableScopeGlobal(t *testing.T) {
+	scope := ctxlog.NewScope("disable-global", ctxlog.EnabledBy("DISABLE_GLOBAL"))
+	ctx := t.Context()
+
+	ctxlog.EnableScopeGlobal(scope)
+	ctxlog.DisableScopeGlobal(scope)
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be inactive after undoing global enablement")
+	}
+
+	// Disabling only undoes enablement, env vars still apply
+	setScopeEnv(t, "DISABLE_GLOBAL", "1")
+	if !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Env var should activate scope after DisableScopeGlobal")
+	}
+}
+
+func TestDenyScopeGlobal(t *testing.T) {
+	scope := ctxlog.NewScope("deny-global", ctxlog.EnabledBy("DENY_GLOBAL"))
+	setScopeEnv(t, "DENY_GLOBAL", "1")
+	ctx := t.Context()
+
+	ctxlog.DenyScopeGlobal(scope)
+	t.Cleanup(func() { ctxlog.ResetScopeGlobal(scope) })
+
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Global deny should take precedence over env var")
+	}
+
+	// Context allow takes precedence over global deny
+	enabledCtx := ctxlog.EnableScope(ctx, scope)
+	if !ctxlog.From(enabledCtx, scope).Enabled(enabledCtx, slog.LevelInfo) {
+		t.Error("Context allow should take precedence over global deny")
+	}
+
+	ctxlog.ResetScopeGlobal(scope)
+	if !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Env var should activate scope after reset")
+	}
+}
+
+func TestEnabledByValue(t *testing.T) {
+	scope := ctxlog.NewScope("by-value", ctxlog.EnabledByValue("BY_VALUE", "1", "true"))
+	ctx := t.Context()
+
+	for value, want := range map[string]bool{"1": true, "true": true, "0": false, "false": false, "": false} {
+		setScopeEnv(t, "BY_VALUE", value)
+		if got := ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo); got != want {
+			t.Errorf("BY_VALUE=%q: expected active=%v, got %v", value, want, got)
+		}
+	}
+}
+
+func TestEnabledByTruthy(t *testing.T) {
+	scope := ctxlog.NewScope("by-truthy", ctxlog.EnabledByTruthy("BY_TRUTHY"))
+	ctx := t.Context()
+
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be inactive when env var is unset")
+	}
+
+	for value, want := range map[string]bool{
+		"1": true, "TRUE": true, "t": true,
+		"0": false, "false": false, "": false, "yes": false,
+	} {
+		setScopeEnv(t, "BY_TRUTHY", value)
+		if got := ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo); got != want {
+			t.Errorf("BY_TRUTHY=%q: expected active=%v, got %v", value, want, got)
+		}
+	}
+}
+
+func TestEnabledByFunc(t *testing.T) {
+	type adminKey struct{}
+	scope := ctxlog.NewScope("by-func",
+		ctxlog.EnabledBy("BY_FUNC"),
+		ctxlog.EnabledByFunc(func(ctx context.Context) bool {
+			return ctx.Value(adminKey{}) != nil
+		}))
+
+	ctx := t.Context()
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be inactive when predicate returns false")
+	}
+
+	adminCtx := context.WithValue(ctx, adminKey{}, true)
+	if !ctxlog.From(adminCtx, scope).Enabled(adminCtx, slog.LevelInfo) {
+		t.Error("Scope should be active when predicate returns true")
+	}
+
+	// Composes with env var conditions using OR logic
+	setScopeEnv(t, "BY_FUNC", "1")
+	if !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be active when env var is set")
+	}
+}
+
+func TestRefreshScopes(t *testing.T) {
+	scope := ctxlog.NewScope("refresh", ctxlog.EnabledBy("REFRESH_SCOPE"))
+	ctx := t.Context()
+
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be inactive without environment variable")
+	}
+
+	// Environment variables are snapshotted on first use
+	t.Cleanup(ctxlog.RefreshScopes) // refresh again once the variable is restored
+	t.Setenv("REFRESH_SCOPE", "1")
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should use the snapshot until refreshed")
+	}
+
+	ctxlog.RefreshScopes()
+	if !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be active after refresh")
+	}
+}
+
+func TestWatchScopes(t *testing.T) {
+	scope := ctxlog.NewScope("watch", ctxlog.EnabledBy("WATCH_SCOPE"))
+	ctx := t.Context()
+
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be inactive without environment variable")
+	}
+
+	t.Cleanup(ctxlog.RefreshScopes) // refresh again once the variable is restored
+	t.Setenv("WATCH_SCOPE", "1")
+	ctxlog.WatchScopes(ctx, time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		if time.Now().After(deadline) {
+			t.Fatal("Watcher should refresh scopes")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestEnableScopeGlobalFor(t *testing.T) {
+	clock := useFakeClock(t)
+	scope := ctxlog.NewScope("global-for")
+	t.Cleanup(func() { ctxlog.ResetScopeGlobal(scope) })
+	ctx := t.Context()
+
+	var mu sync.Mutex
+	var expired []string
+	t.Cleanup(ctxlog.OnScopeExpired(func(s *ctxlog.Scope) {
+		mu.Lock()
+		defer mu.Unlock()
+		expired = append(expired, s.Name())
+	}))
+
+	ctxlog.EnableScopeGlobalFor(30*time.Millisecond, scope)
+	if !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be active before expiration")
+	}
+
+	ttl, ok := scope.GlobalTTL()
+	if !ok || ttl != 30*time.Millisecond {
+		t.Errorf("Expected remaining TTL, got %v, %v", ttl, ok)
+	}
+
+	found := false
+	for _, s := range ctxlog.GetGlobalEnabledScopes() {
+		if s == scope {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Scope should be listed as globally enabled before expiration")
+	}
+
+	clock.Advance(30 * time.Millisecond)
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be inactive after expiration")
+	}
+	if _, ok := scope.GlobalTTL(); ok {
+		t.Error("Expired scope should have no TTL")
+	}
+	for _, s := range ctxlog.GetGlobalEnabledScopes() {
+		if s == scope {
+			t.Error("Expired scope should not be listed as globally enabled")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	count := 0
+	for _, name := range expired {
+		if name == "global-for" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("Expected one expiration event, got %d", count)
+	}
+
+	// Permanent enablement has no TTL
+	ctxlog.EnableScopeGlobal(scope)
+	if _, ok := scope.GlobalTTL(); ok {
+		t.Error("Scope enabled without TTL should report no TTL")
+	}
+}
+
+func TestEnableScopeGlobalForReenable(t *testing.T) {
+	scope := ctxlog.NewScope("global-for-reenable")
+	t.Cleanup(func() { ctxlog.ResetScopeGlobal(scope) })
+	ctx := t.Context()
+
+	for range 100 {
+		ctxlog.EnableScopeGlobalFor(time.Nanosecond, scope)
+		time.Sleep(time.Microsecond)
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			scope.Active(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			ctxlog.EnableScopeGlobal(scope)
+		}()
+		wg.Wait()
+
+		// Expiry of the old deadline must not clear the new enablement
+		if !scope.Active(ctx) {
+			t.Fatal("Re-enabled scope should stay active")
+		}
+	}
+}
+
+func TestEnableScopeFor(t *testing.T) {
+	scope := ctxlog.NewScope("context-for")
+	clock := useFakeClock(t)
+	ctx := ctxlog.EnableScopeFor(t.Context(), 30*time.Millisecond, scope)
+
+	if !ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be active before deadline")
+	}
+
+	clock.Advance(30 * time.Millisecond)
+	if ctxlog.From(ctx, scope).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Scope should be inactive after deadline")
+	}
+}
+
+func TestEnableScopeForShadowedRule(t *testing.T) {
+	enabled := ctxlog.NewScope("context-for-enabled")
+	disabled := ctxlog.NewScope("context-for-disabled")
+	clock := useFakeClock(t)
+	ctx := ctxlog.EnableScope(t.Context(), enabled)
+	ctx = ctxlog.DisableScope(ctx, disabled)
+	ctx = ctxlog.EnableScopeFor(ctx, 30*time.Millisecond, enabled, disabled)
+
+	if !ctxlog.From(ctx, disabled).Enabled(ctx, slog.LevelInfo) {
+		t.Error("Timed rule should override disabled rule before deadline")
+	}
+
+	clock.Advance(30 * time.Millisecond)
+	if a := enabled.Explain(ctx); !a.Active || a.Rule != ctxlog.ScopeRuleContextAllow {
+		t.Errorf("Shadowed enable rule should apply after deadline, got %v", a)
+	}
+	if a := disabled.Explain(ctx); a.Active || a.Rule != ctxlog.ScopeRuleContextDeny {
+		t.Errorf("Shadowed disable rule should apply after deadline, got %v", a)
+	}
+}
+
+func TestScopeIntrospection(t *testing.T) {
+	parent := ctxlog.NewScope("intro", ctxlog.EnabledBy("INTRO_A", "INTRO_B"))
+	child := parent.NewChild("child")
+	grandChild := child.NewChild("grandchild")
+
+	if child.Parent() != parent || parent.Parent() != nil {
+		t.Error("Parent not wired correctly")
+	}
+	if children := parent.Children(); len(children) != 1 || children[0] != child {
+		t.Errorf("Unexpected children: %v", children)
+	}
+	if envVars := parent.EnvVars(); len(envVars) != 2 || envVars[0] != "INTRO_A" || envVars[1] != "INTRO_B" {
+		t.Errorf("Unexpected env vars: %v", envVars)
+	}
+
+	found, ok := ctxlog.LookupScope("intro.child.grandchild")
+	if !ok || found != grandChild {
+		t.Error("LookupScope should return registered scope")
+	}
+	if _, ok := ctxlog.LookupScope("intro.unknown"); ok {
+		t.Error("LookupScope should not find unregistered scope")
+	}
+
+	names := map[string]bool{}
+	for _, scope := range ctxlog.Scopes() {
+		names[scope.Name()] = true
+	}
+	for _, name := range []string{"intro", "intro.child", "intro.child.grandchild"} {
+		if !names[name] {
+			t.Errorf("Scopes should include %s", name)
+		}
+	}
+}
+
+func TestScopeExplain(t *testing.T) {
+	parent := ctxlog.NewScope("explain")
+	child := parent.NewChild("child", ctxlog.EnabledBy("EXPLAIN_CHILD"))
+	ctx := t.Context()
+
+	if a := child.Explain(ctx); a.Active || a.Rule != ctxlog.ScopeRuleNone || a.Scope != nil {
+		t.Errorf("Expected inactive with no rule, got %v", a)
+	}
+	if child.Active(ctx) {
+		t.Error("Scope should be inactive")
+	}
+
+	enabledCtx := ctxlog.EnableScope(ctx, parent)
+	a := child.Explain(enabledCtx)
+	if !a.Active || a.Rule != ctxlog.ScopeRuleContextAllow || a.Scope != parent {
+		t.Errorf("Expected activation inherited from parent, got %v", a)
+	}
+	if a.String() != "active by context-allow of explain" {
+		t.Errorf("Unexpected explanation: %s", a)
+	}
+
+	deniedCtx := ctxlog.DisableScope(enabledCtx, child)
+	if a := child.Explain(deniedCtx); a.Active || a.Rule != ctxlog.ScopeRuleContextDeny || a.Scope != child {
+		t.Errorf("Expected context deny, got %v", a)
+	}
+
+	ctxlog.DenyScopeGlobal(parent)
+	t.Cleanup(func() { ctxlog.ResetScopeGlobal(parent) })
+	if a := child.Explain(ctx); a.Active || a.Rule != ctxlog.ScopeRuleGlobalDeny || a.Scope != parent {
+		t.Errorf("Expected global deny inherited from parent, got %v", a)
+	}
+	ctxlog.ResetScopeGlobal(parent)
+
+	setScopeEnv(t, "EXPLAIN_CHILD", "1")
+	if a := child.Explain(ctx); !a.Active || a.Rule != ctxlog.ScopeRuleEnv || a.Scope != child {
+		t.Errorf("Expected env activation, got %v", a)
+	}
+	if !child.Active(ctx) {
+		t.Error("Scope should be active")
+	}
+}
+
+func TestScopeHandler(t *testing.T) {
+	var base, database, query, runtime bytes.Buffer
+	textHandler := func(w *bytes.Buffer) slog.Handler {
+		return slog.NewTextHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug})
+	}
+
+	// Handlers differ on every run, so use a unique name to avoid conflicts under -count
+	name := fmt.Sprintf("handler_database_%d", time.Now().UnixNano())
+	dbScope := ctxlog.NewScope(name, ctxlog.WithHandler(textHandler(&database)))
+	connScope := dbScope.NewChild("conn")
+	queryScope := dbScope.NewChild("query", ctxlog.WithHandler(textHandler(&query)))
+
+	ctx := ctxlog.With(t.Context(), slog.New(textHandler(&base)))
+	ctx = ctxlog.WithAttrs(ctx, slog.String("request_id", "r1"))
+	ctx = ctxlog.EnableScope(ctx, dbScope)
+
+	ctxlog.From(ctx).Info("request")
+	ctxlog.From(ctx, dbScope).Debug("connect")
+	ctxlog.From(ctx, connScope).Debug("pool")
+	ctxlog.From(ctx, queryScope).Debug("select")
+
+	if output := base.String(); !strings.Contains(output, "msg=request") || strings.Contains(output, "ctxlog.scope") {
+		t.Errorf("Expected base handler to get only unscoped logs, got %q", output)
+	}
+	if output := database.String(); !strings.Contains(output, "msg=connect request_id=r1 ctxlog.scope="+name) ||
+		!strings.Contains(output, "msg=pool request_id=r1 ctxlog.scope="+name+".conn") ||
+		strings.Contains(output, "select") {
+		t.Errorf("Expected scope and inheriting child logs in scope handler, got %q", output)
+	}
+	if output := query.String(); !strings.Contains(output, "msg=select request_id=r1 ctxlog.scope="+name+".query") {
+		t.Errorf("Expected overriding child logs in its own handler, got %q", output)
+	}
+
+	// Runtime handler takes precedence and is inherited
+	ctxlog.SetScopeHandler(dbScope, textHandler(&runtime))
+	ctxlog.From(ctx, connScope).Debug("runtime")
+	if !strings.Contains(runtime.String(), "msg=runtime") || strings.Contains(database.String(), "runtime") {
+		t.Errorf("Expected runtime handler to receive child logs, got %q", runtime.String())
+	}
+
+	ctxlog.ResetScopeHandler(dbScope)
+	ctxlog.From(ctx, dbScope).Debug("reset")
+	if !strings.Contains(database.String(), "msg=reset") {
+		t.Errorf("Expected option handler after reset, got %q", database.String())
+	}
+}
+
+// setScopeEnv sets an environment variable for the test and refreshes the
+// scope snapshots, both now and after the variable is restored, so that other
+// tests and repeated runs do not see a stale snapshot.
+func setScopeEnv(t *testing.T, key, value string) {
+	t.Helper()
+
+	// Cleanups run in reverse order, so this runs after Setenv restores the variable
+	t.Cleanup(ctxlog.RefreshScopes)
+	t.Setenv(key, value)
+	ctxlog.RefreshScopes()
+}
+
+// fakeClock is a manually advanced clock for scope deadlines.
+type fakeClock struct {
+	now atomic.Int64
+}
+
+// useFakeClock replaces the clock of scope deadlines until the test ends.
+func useFakeClock(t *testing.T) *fakeClock {
+	t.Helper()
+
+	clock := &fakeClock{}
+	clock.now.Store(time.Now().UnixNano())
+	ctxlog.SetNow(t, clock.Now)
+	return clock
+}
+
+// Now returns the current time of the clock.
+func (c *fakeClock) Now() time.Time {
+	return time.Unix(0, c.now.Load())
+}
+
+// Advance moves the clock forward by d.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now.Add(int64(d))
+}